@@ -19,6 +19,7 @@ package v1alpha1
 import (
 	appv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -30,26 +31,152 @@ type ApolloSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
+	// Version is the apollo version of every component, it replaces the tag of the images.
+	// The operator upgrades the components one after the other, each Deployment waits for the previous ones to become available.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// DeletionPolicy decides what happens to the PVC of the database when the Apollo is deleted, it is retained by default.
+	// Snapshot backs up the databases with an ApolloBackup before the PVC is deleted.
+	// +optional
+	// +kubebuilder:validation:Enum=Delete;Retain;Snapshot
+	// +kubebuilder:default=Retain
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty" default:"Retain"`
+
+	// NetworkPolicy isolates the pods of the Apollo with NetworkPolicies.
+	// +optional
+	NetworkPolicy NetworkPolicy `json:"networkPolicy,omitempty"`
+
 	ConfigService ConfigService `json:"configService,omitempty"`
 
 	AdminService AdminService `json:"adminService,omitempty"`
 
 	PortalService PortalService `json:"portalService,omitempty"`
+
+	// Database configures the database deployed by the operator for the components.
+	// +optional
+	Database Database `json:"database,omitempty"`
 }
 
-type PortalService struct {
+// Database describes the database of the all-in-one kind.
+type Database struct {
+	// Mode selects where the database runs: mysql deploys a StatefulSet with a PVC, embedded deploys the database of Type
+	// as a Deployment keeping its data in a memory emptyDir, without StatefulSet or PVC, so that the data is lost when
+	// the pod restarts, and external connects to an existing database. The embedded mode does not use H2, the config,
+	// admin and portal services run in separate pods which cannot share an in-memory H2 database.
+	// +optional
+	// +kubebuilder:validation:Enum=embedded;mysql;external
+	Mode DatabaseMode `json:"mode,omitempty" default:"mysql"`
+
+	// Type is the kind of database, it selects the JDBC url, the driver and the default port.
+	// The schema of postgresql is created from the sql configmap on the first start of an empty database only,
+	// it is not migrated when the version changes.
+	// +optional
+	// +kubebuilder:validation:Enum=mysql;postgresql
+	Type DatabaseType `json:"type,omitempty" default:"mysql"`
+
+	// Image of the database, defaults to the image of the database type.
+	// +optional
 	Image string `json:"image,omitempty"`
 
+	// Storage configures the PVC of the database StatefulSet in the mysql mode.
+	// +optional
+	Storage DatabaseStorage `json:"storage,omitempty"`
+
+	// PersistentVolumeClaimRetentionPolicy describes the lifecycle of the PVC of the database StatefulSet,
+	// by default the PVC is retained when the StatefulSet is deleted.
+	// +optional
+	PersistentVolumeClaimRetentionPolicy *appv1.StatefulSetPersistentVolumeClaimRetentionPolicy `json:"persistentVolumeClaimRetentionPolicy,omitempty"`
+
+	// Config is added to the configuration of mysql as a my.cnf file, it is not supported by postgresql.
+	// +optional
+	Config string `json:"config,omitempty"`
+
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// PodTemplate customizes the pods of the database.
+	// +optional
+	PodTemplate PodTemplateOverrides `json:"podTemplate,omitempty"`
+
+	// ServiceAccount runs the pods of the database.
+	// +optional
+	ServiceAccount ServiceAccount `json:"serviceAccount,omitempty"`
+
+	// External is the existing database used in the external mode.
+	// +optional
+	External ExternalDatabase `json:"external,omitempty"`
+}
+
+// DatabaseStorage describes the PVC of the database.
+type DatabaseStorage struct {
+	// Size of the PVC, defaults to 2Gi. The size can only grow, the existing PVC is expanded in place
+	// which requires a storage class allowing volume expansion.
+	// +optional
+	Size resource.Quantity `json:"size,omitempty"`
+
+	// StorageClassName of the PVC, the default storage class of the cluster is used when empty.
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+}
+
+// DatabaseMode is where the database of the all-in-one kind runs.
+type DatabaseMode string
+
+const (
+	DatabaseModeEmbedded DatabaseMode = "embedded"
+	DatabaseModeMySQL    DatabaseMode = "mysql"
+	DatabaseModeExternal DatabaseMode = "external"
+)
+
+// ExternalDatabase is an existing database holding both the ApolloConfigDB and the ApolloPortalDB.
+type ExternalDatabase struct {
+	Host string `json:"host,omitempty"`
+
+	// Port defaults to the port of the database type.
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// UsernameSecretRef selects the key of a Secret holding the database username.
+	UsernameSecretRef *corev1.SecretKeySelector `json:"usernameSecretRef,omitempty"`
+
+	// PasswordSecretRef selects the key of a Secret holding the database password.
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+
+	ConfigDBName               string `json:"configDBName,omitempty" default:"ApolloConfigDB"`
+	PortalDBName               string `json:"portalDBName,omitempty" default:"ApolloPortalDB"`
+	ConnectionStringProperties string `json:"connectionStringProperties,omitempty"`
+}
+
+type PortalService struct {
+	Image string `json:"image,omitempty" default:"apolloconfig/apollo-portal:2.1.0"`
+
 	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
 
 	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
 
-	Replicas int32 `json:"replicas,omitempty"`
+	Replicas int32 `json:"replicas,omitempty" default:"1"`
 
-	ContainerPort int32 `json:"containerPort,omitempty"`
+	// Autoscaling scales the Deployment with a HorizontalPodAutoscaler instead of Replicas.
+	// +optional
+	Autoscaling Autoscaling `json:"autoscaling,omitempty"`
+
+	// PodDisruptionBudget limits the pods evicted at once by a node drain.
+	// +optional
+	PodDisruptionBudget PodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
+
+	ContainerPort int32 `json:"containerPort,omitempty" default:"8070"`
 
 	Strategy appv1.DeploymentStrategy `json:"strategy,omitempty"`
 
+	// DisableConfigRollout keeps the pods running when the content of their ConfigMaps or Secrets changes,
+	// by default a content change rolls the pods.
+	// +optional
+	DisableConfigRollout bool `json:"disableConfigRollout,omitempty"`
+
 	Env []corev1.EnvVar `json:"env,omitempty"`
 
 	Service Service `json:"service,omitempty"`
@@ -66,9 +193,21 @@ type PortalService struct {
 
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 
+	// PodTemplate customizes the pods of the ApolloPortal.
+	// +optional
+	PodTemplate PodTemplateOverrides `json:"podTemplate,omitempty"`
+
+	// ServiceAccount runs the pods of the ApolloPortal.
+	// +optional
+	ServiceAccount ServiceAccount `json:"serviceAccount,omitempty"`
+
 	// Ingress is used to specify how ApolloAdmin is exposed.
 	// +optional
 	Ingress Ingress `json:"ingress,omitempty"`
+
+	// Exposure selects how the ApolloPortal is exposed outside of the cluster, defaults to the Ingress.
+	// +optional
+	Exposure Exposure `json:"exposure,omitempty"`
 }
 
 type PortalServiceConfig struct {
@@ -82,10 +221,43 @@ type PortalServiceConfig struct {
 type ApolloStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
+
+	// ObservedGeneration is the most recent generation observed by the operator.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the Apollo state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ConfigService is the observed state of the config service.
+	// +optional
+	ConfigService ComponentStatus `json:"configService,omitempty"`
+
+	// AdminService is the observed state of the admin service.
+	// +optional
+	AdminService ComponentStatus `json:"adminService,omitempty"`
+
+	// PortalService is the observed state of the portal service.
+	// +optional
+	PortalService ComponentStatus `json:"portalService,omitempty"`
+
+	// Version is the apollo version the components are running and upgraded to.
+	// +optional
+	Version VersionStatus `json:"version,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+//+kubebuilder:printcolumn:name="Config",type="string",JSONPath=".status.configService.ready"
+//+kubebuilder:printcolumn:name="Admin",type="string",JSONPath=".status.adminService.ready"
+//+kubebuilder:printcolumn:name="Portal",type="string",JSONPath=".status.portalService.ready"
+//+kubebuilder:printcolumn:name="Portal URL",type="string",JSONPath=".status.portalService.url",priority=1
+//+kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version.current"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Apollo is the Schema for the apolloes API
 type Apollo struct {
@@ -0,0 +1,151 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// defaultDatabaseStorageSize is the size of the PVC of the all-in-one database.
+const defaultDatabaseStorageSize = "2Gi"
+
+// log is for logging in this package.
+var apollolog = logf.Log.WithName("apollo-resource")
+
+func (r *Apollo) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-apolloconfig-com-v1alpha1-apollo,mutating=true,failurePolicy=fail,sideEffects=None,groups=apolloconfig.com,resources=apolloes,verbs=create;update,versions=v1alpha1,name=mapollo.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &Apollo{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type.
+// It is also called by the controller, so it must stay idempotent and must not log.
+func (r *Apollo) Default() {
+	setDefaults(&r.Spec)
+	if r.Spec.Database.Mode == DatabaseModeExternal && r.Spec.Database.External.Port == 0 {
+		r.Spec.Database.External.Port = r.Spec.Database.Type.DefaultPort()
+	}
+	if r.Spec.Database.Image == "" {
+		r.Spec.Database.Image = r.Spec.Database.Type.DefaultImage()
+	}
+	if r.Spec.Database.Storage.Size.IsZero() {
+		r.Spec.Database.Storage.Size = resource.MustParse(defaultDatabaseStorageSize)
+	}
+	defaultImage(&r.Spec.ConfigService.Image, r.Spec.Version)
+	defaultImage(&r.Spec.AdminService.Image, r.Spec.Version)
+	defaultImage(&r.Spec.PortalService.Image, r.Spec.Version)
+	defaultService(&r.Spec.ConfigService.Service, r.Spec.ConfigService.ContainerPort)
+	defaultService(&r.Spec.AdminService.Service, r.Spec.AdminService.ContainerPort)
+	defaultService(&r.Spec.PortalService.Service, r.Spec.PortalService.ContainerPort)
+	defaultProbe(&r.Spec.ConfigService.Probe)
+	defaultProbe(&r.Spec.AdminService.Probe)
+	defaultProbe(&r.Spec.PortalService.Probe)
+}
+
+//+kubebuilder:webhook:path=/validate-apolloconfig-com-v1alpha1-apollo,mutating=false,failurePolicy=fail,sideEffects=None,groups=apolloconfig.com,resources=apolloes,verbs=create;update,versions=v1alpha1,name=vapollo.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &Apollo{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *Apollo) ValidateCreate() error {
+	apollolog.Info("validate create", "name", r.Name)
+
+	return r.toAggregate(r.validateSpec())
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *Apollo) ValidateUpdate(old runtime.Object) error {
+	apollolog.Info("validate update", "name", r.Name)
+
+	allErrs := r.validateSpec()
+	if oldApollo, ok := old.(*Apollo); ok {
+		// NOTE 数据库的数据保存在pvc中，切换数据库类型会丢失全部数据
+		oldApollo = oldApollo.DeepCopy()
+		oldApollo.Default()
+		allErrs = append(allErrs, validateImmutable(field.NewPath("spec", "database", "type"), r.Spec.Database.Type, oldApollo.Spec.Database.Type)...)
+		allErrs = append(allErrs, validateImmutable(field.NewPath("spec", "database", "mode"), r.Spec.Database.Mode, oldApollo.Spec.Database.Mode)...)
+		if r.Spec.Database.Storage.Size.Cmp(oldApollo.Spec.Database.Storage.Size) < 0 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "database", "storage", "size"), r.Spec.Database.Storage.Size.String(), "the PVC cannot be shrunk"))
+		}
+	}
+	return r.toAggregate(allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *Apollo) ValidateDelete() error {
+	return nil
+}
+
+func (r *Apollo) validateSpec() field.ErrorList {
+	spec := field.NewPath("spec")
+	allErrs := field.ErrorList{}
+	allErrs = append(allErrs, validateVersion(spec.Child("version"), r.Spec.Version)...)
+	allErrs = append(allErrs, validateDeletionPolicy(spec.Child("deletionPolicy"), r.Spec.Database.Type, r.Spec.DeletionPolicy)...)
+	allErrs = append(allErrs, validateNetworkPolicy(spec.Child("networkPolicy"), r.Spec.NetworkPolicy)...)
+	allErrs = append(allErrs, validateAutoscaling(spec.Child("configService", "autoscaling"), r.Spec.ConfigService.Autoscaling)...)
+	allErrs = append(allErrs, validatePodDisruptionBudget(spec.Child("configService", "podDisruptionBudget"), r.Spec.ConfigService.PodDisruptionBudget)...)
+	allErrs = append(allErrs, validateAutoscaling(spec.Child("adminService", "autoscaling"), r.Spec.AdminService.Autoscaling)...)
+	allErrs = append(allErrs, validatePodDisruptionBudget(spec.Child("adminService", "podDisruptionBudget"), r.Spec.AdminService.PodDisruptionBudget)...)
+	allErrs = append(allErrs, validateAutoscaling(spec.Child("portalService", "autoscaling"), r.Spec.PortalService.Autoscaling)...)
+	allErrs = append(allErrs, validatePodDisruptionBudget(spec.Child("portalService", "podDisruptionBudget"), r.Spec.PortalService.PodDisruptionBudget)...)
+	if r.Spec.Database.Config != "" && r.Spec.Database.Type == DatabaseTypePostgreSQL {
+		allErrs = append(allErrs, field.Invalid(spec.Child("database", "config"), r.Spec.Database.Config, "config is only supported for mysql"))
+	}
+	if r.Spec.Database.Mode == DatabaseModeExternal {
+		external := spec.Child("database", "external")
+		if r.Spec.Database.External.Host == "" {
+			allErrs = append(allErrs, field.Required(external.Child("host"), "host is required when database.mode is external"))
+		}
+		if r.Spec.Database.External.UsernameSecretRef == nil {
+			allErrs = append(allErrs, field.Required(external.Child("usernameSecretRef"), "usernameSecretRef is required when database.mode is external"))
+		}
+		if r.Spec.Database.External.PasswordSecretRef == nil {
+			allErrs = append(allErrs, field.Required(external.Child("passwordSecretRef"), "passwordSecretRef is required when database.mode is external"))
+		}
+	}
+	allErrs = append(allErrs, validateIngress(spec.Child("configService", "ingress"), r.Spec.ConfigService.Ingress)...)
+	allErrs = append(allErrs, validateExposure(spec.Child("configService", "exposure"), r.Spec.ConfigService.Exposure, r.Spec.ConfigService.Ingress)...)
+	allErrs = append(allErrs, validateIngress(spec.Child("adminService", "ingress"), r.Spec.AdminService.Ingress)...)
+	allErrs = append(allErrs, validateExposure(spec.Child("adminService", "exposure"), r.Spec.AdminService.Exposure, r.Spec.AdminService.Ingress)...)
+	allErrs = append(allErrs, validateIngress(spec.Child("portalService", "ingress"), r.Spec.PortalService.Ingress)...)
+	allErrs = append(allErrs, validateExposure(spec.Child("portalService", "exposure"), r.Spec.PortalService.Exposure, r.Spec.PortalService.Ingress)...)
+	allErrs = append(allErrs, validateContextPath(spec.Child("configService", "config", "contextPath"), r.Spec.ConfigService.Config.ContextPath)...)
+	allErrs = append(allErrs, validateContextPath(spec.Child("adminService", "config", "contextPath"), r.Spec.AdminService.Config.ContextPath)...)
+	allErrs = append(allErrs, validateContextPath(spec.Child("portalService", "config", "contextPath"), r.Spec.PortalService.Config.ContextPath)...)
+	allErrs = append(allErrs, validateServiceAccount(spec.Child("configService", "serviceAccount"), r.Spec.ConfigService.ServiceAccount)...)
+	allErrs = append(allErrs, validateServiceAccount(spec.Child("adminService", "serviceAccount"), r.Spec.AdminService.ServiceAccount)...)
+	allErrs = append(allErrs, validateServiceAccount(spec.Child("portalService", "serviceAccount"), r.Spec.PortalService.ServiceAccount)...)
+	allErrs = append(allErrs, validateServiceAccount(spec.Child("database", "serviceAccount"), r.Spec.Database.ServiceAccount)...)
+	return allErrs
+}
+
+func (r *Apollo) toAggregate(allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return k8serrors.NewInvalid(GroupVersion.WithKind("Apollo").GroupKind(), r.Name, allErrs)
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackupTarget references the apollo resource whose databases are backed up.
+type BackupTarget struct {
+	// Kind of the apollo resource. Apollo backs up the ApolloConfigDB and the ApolloPortalDB of the all-in-one database,
+	// ApolloEnvironment its ConfigDB and ApolloPortal its PortalDB.
+	// +kubebuilder:validation:Enum=Apollo;ApolloEnvironment;ApolloPortal
+	Kind string `json:"kind"`
+
+	// Name of the apollo resource, in the namespace of the backup.
+	Name string `json:"name"`
+}
+
+// BackupStorage describes the PVC the compressed dumps are written to.
+type BackupStorage struct {
+	// ClaimName is the name of the PVC holding the dumps. The PVC is created by the operator when it does not exist,
+	// and is kept when the backup is deleted. Defaults to <name>-backup-pvc.
+	// +optional
+	ClaimName string `json:"claimName,omitempty"`
+
+	// Size of the PVC created by the operator.
+	// +optional
+	// +kubebuilder:default="5Gi"
+	Size resource.Quantity `json:"size,omitempty"`
+
+	// StorageClassName of the PVC created by the operator, the default storage class of the cluster is used when empty.
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+}
+
+// ApolloBackupSpec defines the desired state of ApolloBackup
+type ApolloBackupSpec struct {
+	// Target is the apollo resource whose databases are dumped.
+	Target BackupTarget `json:"target"`
+
+	// Storage is the PVC the dumps are written to.
+	// +optional
+	Storage BackupStorage `json:"storage,omitempty"`
+
+	// Image runs mysqldump.
+	// +optional
+	// +kubebuilder:default="mysql:5.7"
+	Image string `json:"image,omitempty"`
+}
+
+// BackupPhase is the lifecycle phase of a backup or a restore.
+type BackupPhase string
+
+const (
+	BackupPhasePending   BackupPhase = "Pending"
+	BackupPhaseRunning   BackupPhase = "Running"
+	BackupPhaseCompleted BackupPhase = "Completed"
+	BackupPhaseFailed    BackupPhase = "Failed"
+)
+
+// ApolloBackupStatus defines the observed state of ApolloBackup
+type ApolloBackupStatus struct {
+	// Phase of the backup.
+	// +optional
+	Phase BackupPhase `json:"phase,omitempty"`
+
+	// ClaimName is the PVC holding the dumps.
+	// +optional
+	ClaimName string `json:"claimName,omitempty"`
+
+	// Path is the directory of the dumps in the PVC, it holds one <database>.sql.gz file per database.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Databases are the names of the dumped databases.
+	// +optional
+	Databases []string `json:"databases,omitempty"`
+
+	// StartTime is when the backup job started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when the backup job completed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Message explains the phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Target",type="string",JSONPath=".spec.target.name"
+//+kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+//+kubebuilder:printcolumn:name="Path",type="string",JSONPath=".status.path",priority=1
+//+kubebuilder:printcolumn:name="Completed",type="date",JSONPath=".status.completionTime"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ApolloBackup is the Schema for the apollobackups API
+type ApolloBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ApolloBackupSpec   `json:"spec,omitempty"`
+	Status ApolloBackupStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ApolloBackupList contains a list of ApolloBackup
+type ApolloBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ApolloBackup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ApolloBackup{}, &ApolloBackupList{})
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ApolloBackupScheduleSpec defines the desired state of ApolloBackupSchedule
+type ApolloBackupScheduleSpec struct {
+	// Schedule in cron format, e.g. "0 3 * * *".
+	Schedule string `json:"schedule"`
+
+	// Suspend stops the scheduling of new backups, running backups are not affected.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// Retention is the number of backups kept, the dumps and the ApolloBackups of older backups are deleted.
+	// +optional
+	// +kubebuilder:default=7
+	// +kubebuilder:validation:Minimum=1
+	Retention int32 `json:"retention,omitempty"`
+
+	// Template describes the scheduled backups.
+	Template ApolloBackupSpec `json:"template"`
+}
+
+// ApolloBackupScheduleStatus defines the observed state of ApolloBackupSchedule
+type ApolloBackupScheduleStatus struct {
+	// LastScheduleTime is when a backup was last scheduled.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// LastBackup is the name of the latest ApolloBackup created by the schedule.
+	// +optional
+	LastBackup string `json:"lastBackup,omitempty"`
+
+	// LastSuccessfulBackup is the name of the latest completed ApolloBackup created by the schedule.
+	// +optional
+	LastSuccessfulBackup string `json:"lastSuccessfulBackup,omitempty"`
+
+	// Backups are the names of the retained ApolloBackups, newest first.
+	// +optional
+	Backups []string `json:"backups,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule"
+//+kubebuilder:printcolumn:name="Suspend",type="boolean",JSONPath=".spec.suspend"
+//+kubebuilder:printcolumn:name="Last Backup",type="string",JSONPath=".status.lastBackup"
+//+kubebuilder:printcolumn:name="Last Schedule",type="date",JSONPath=".status.lastScheduleTime"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ApolloBackupSchedule is the Schema for the apollobackupschedules API
+type ApolloBackupSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ApolloBackupScheduleSpec   `json:"spec,omitempty"`
+	Status ApolloBackupScheduleStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ApolloBackupScheduleList contains a list of ApolloBackupSchedule
+type ApolloBackupScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ApolloBackupSchedule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ApolloBackupSchedule{}, &ApolloBackupScheduleList{})
+}
@@ -30,6 +30,22 @@ type ApolloEnvironmentSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
+	// Version is the apollo version of every component, it replaces the tag of the images.
+	// The operator upgrades the components one after the other, each Deployment waits for the previous ones to become available.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// DeletionPolicy decides what happens to the resources which cannot be owned by the ApolloEnvironment when it is deleted.
+	// Snapshot backs up the ApolloConfigDB with an ApolloBackup first.
+	// +optional
+	// +kubebuilder:validation:Enum=Delete;Retain;Snapshot
+	// +kubebuilder:default=Delete
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty" default:"Delete"`
+
+	// NetworkPolicy isolates the pods of the ApolloEnvironment with NetworkPolicies.
+	// +optional
+	NetworkPolicy NetworkPolicy `json:"networkPolicy,omitempty"`
+
 	ConfigDB ConfigDB `json:"configdb,omitempty"`
 
 	ConfigService ConfigService `json:"configService,omitempty"`
@@ -38,19 +54,41 @@ type ApolloEnvironmentSpec struct {
 }
 
 type ConfigDB struct {
-	Username                   string          `json:"username,omitempty"`
-	Password                   string          `json:"password,omitempty"`
+	// Type is the kind of database, it selects the JDBC url, the driver and the default port.
+	// The schema of postgresql is not managed by the operator, schema.manage is only supported for mysql.
+	// +optional
+	// +kubebuilder:validation:Enum=mysql;postgresql
+	Type DatabaseType `json:"type,omitempty" default:"mysql"`
+
+	Username string `json:"username,omitempty"`
+
+	// Password is the plaintext database password.
+	// Deprecated: use PasswordSecretRef, a plaintext password is copied into an operator managed Secret.
+	Password string `json:"password,omitempty"`
+
+	// UsernameSecretRef selects the key of a Secret holding the database username, it takes precedence over Username.
+	// +optional
+	UsernameSecretRef *corev1.SecretKeySelector `json:"usernameSecretRef,omitempty"`
+
+	// PasswordSecretRef selects the key of a Secret holding the database password, it takes precedence over Password.
+	// +optional
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+
 	Host                       string          `json:"host,omitempty"`
 	Port                       int32           `json:"port,omitempty"`
 	DBName                     string          `json:"dbName,omitempty"`
 	ConnectionStringProperties string          `json:"connectionStringProperties,omitempty"`
 	Service                    ConfigDBService `json:"service,omitempty"`
+
+	// Schema configures the management of the ConfigDB schema by the operator.
+	// +optional
+	Schema Schema `json:"schema,omitempty"`
 }
 
 type ConfigDBService struct {
 	Name string             `json:"name,omitempty"`
 	Port int32              `json:"port,omitempty"`
-	Type corev1.ServiceType `json:"type,omitempty"`
+	Type corev1.ServiceType `json:"type,omitempty" default:"ClusterIP"`
 }
 
 type ConfigService struct {
@@ -62,10 +100,23 @@ type ConfigService struct {
 
 	Replicas int32 `json:"replicas,omitempty" default:"1"`
 
-	ContainerPort int32 `json:"containerPort,omitempty"`
+	// Autoscaling scales the Deployment with a HorizontalPodAutoscaler instead of Replicas.
+	// +optional
+	Autoscaling Autoscaling `json:"autoscaling,omitempty"`
+
+	// PodDisruptionBudget limits the pods evicted at once by a node drain.
+	// +optional
+	PodDisruptionBudget PodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
+
+	ContainerPort int32 `json:"containerPort,omitempty" default:"8080"`
 
 	Strategy appv1.DeploymentStrategy `json:"strategy,omitempty"`
 
+	// DisableConfigRollout keeps the pods running when the content of their ConfigMaps or Secrets changes,
+	// by default a content change rolls the pods.
+	// +optional
+	DisableConfigRollout bool `json:"disableConfigRollout,omitempty"`
+
 	Env []corev1.EnvVar `json:"env,omitempty"`
 
 	Service Service `json:"service,omitempty"`
@@ -82,9 +133,21 @@ type ConfigService struct {
 
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 
+	// PodTemplate customizes the pods of the ApolloConfig.
+	// +optional
+	PodTemplate PodTemplateOverrides `json:"podTemplate,omitempty"`
+
+	// ServiceAccount runs the pods of the ApolloConfig.
+	// +optional
+	ServiceAccount ServiceAccount `json:"serviceAccount,omitempty"`
+
 	// Ingress is used to specify how ApolloConfig is exposed.
 	// +optional
 	Ingress Ingress `json:"ingress,omitempty"`
+
+	// Exposure selects how the ApolloConfig is exposed outside of the cluster, defaults to the Ingress.
+	// +optional
+	Exposure Exposure `json:"exposure,omitempty"`
 }
 
 type ConfigServiceConfig struct {
@@ -101,10 +164,23 @@ type AdminService struct {
 
 	Replicas int32 `json:"replicas,omitempty" default:"1"`
 
-	ContainerPort int32 `json:"containerPort,omitempty"`
+	// Autoscaling scales the Deployment with a HorizontalPodAutoscaler instead of Replicas.
+	// +optional
+	Autoscaling Autoscaling `json:"autoscaling,omitempty"`
+
+	// PodDisruptionBudget limits the pods evicted at once by a node drain.
+	// +optional
+	PodDisruptionBudget PodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
+
+	ContainerPort int32 `json:"containerPort,omitempty" default:"8090"`
 
 	Strategy appv1.DeploymentStrategy `json:"strategy,omitempty"`
 
+	// DisableConfigRollout keeps the pods running when the content of their ConfigMaps or Secrets changes,
+	// by default a content change rolls the pods.
+	// +optional
+	DisableConfigRollout bool `json:"disableConfigRollout,omitempty"`
+
 	Env []corev1.EnvVar `json:"env,omitempty"`
 
 	Service Service `json:"service,omitempty"`
@@ -121,9 +197,21 @@ type AdminService struct {
 
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 
+	// PodTemplate customizes the pods of the ApolloAdmin.
+	// +optional
+	PodTemplate PodTemplateOverrides `json:"podTemplate,omitempty"`
+
+	// ServiceAccount runs the pods of the ApolloAdmin.
+	// +optional
+	ServiceAccount ServiceAccount `json:"serviceAccount,omitempty"`
+
 	// Ingress is used to specify how ApolloAdmin is exposed.
 	// +optional
 	Ingress Ingress `json:"ingress,omitempty"`
+
+	// Exposure selects how the ApolloAdmin is exposed outside of the cluster, defaults to the Ingress.
+	// +optional
+	Exposure Exposure `json:"exposure,omitempty"`
 }
 
 type AdminServiceConfig struct {
@@ -135,10 +223,42 @@ type AdminServiceConfig struct {
 type ApolloEnvironmentStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
+
+	// ObservedGeneration is the most recent generation observed by the operator.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the ApolloEnvironment state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ConfigService is the observed state of the config service.
+	// +optional
+	ConfigService ComponentStatus `json:"configService,omitempty"`
+
+	// AdminService is the observed state of the admin service.
+	// +optional
+	AdminService ComponentStatus `json:"adminService,omitempty"`
+
+	// Schema is the state of the ConfigDB schema managed by the operator.
+	// +optional
+	Schema SchemaStatus `json:"schema,omitempty"`
+
+	// Version is the apollo version the components are running and upgraded to.
+	// +optional
+	Version VersionStatus `json:"version,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+//+kubebuilder:printcolumn:name="Config",type="string",JSONPath=".status.configService.ready"
+//+kubebuilder:printcolumn:name="Admin",type="string",JSONPath=".status.adminService.ready"
+//+kubebuilder:printcolumn:name="Config URL",type="string",JSONPath=".status.configService.url",priority=1
+//+kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version.current"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // ApolloEnvironment is the Schema for the apolloenvironments API
 type ApolloEnvironment struct {
@@ -0,0 +1,110 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var apolloenvironmentlog = logf.Log.WithName("apolloenvironment-resource")
+
+func (r *ApolloEnvironment) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-apolloconfig-com-v1alpha1-apolloenvironment,mutating=true,failurePolicy=fail,sideEffects=None,groups=apolloconfig.com,resources=apolloenvironments,verbs=create;update,versions=v1alpha1,name=mapolloenvironment.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &ApolloEnvironment{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type.
+// It is also called by the controller, so it must stay idempotent and must not log.
+func (r *ApolloEnvironment) Default() {
+	setDefaults(&r.Spec)
+	defaultImage(&r.Spec.ConfigService.Image, r.Spec.Version)
+	defaultImage(&r.Spec.AdminService.Image, r.Spec.Version)
+	defaultDatabasePort(r.Spec.ConfigDB.Type, &r.Spec.ConfigDB.Port, &r.Spec.ConfigDB.Service.Port)
+	defaultService(&r.Spec.ConfigService.Service, r.Spec.ConfigService.ContainerPort)
+	defaultService(&r.Spec.AdminService.Service, r.Spec.AdminService.ContainerPort)
+	defaultProbe(&r.Spec.ConfigService.Probe)
+	defaultProbe(&r.Spec.AdminService.Probe)
+}
+
+//+kubebuilder:webhook:path=/validate-apolloconfig-com-v1alpha1-apolloenvironment,mutating=false,failurePolicy=fail,sideEffects=None,groups=apolloconfig.com,resources=apolloenvironments,verbs=create;update,versions=v1alpha1,name=vapolloenvironment.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &ApolloEnvironment{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *ApolloEnvironment) ValidateCreate() error {
+	apolloenvironmentlog.Info("validate create", "name", r.Name)
+
+	return r.toAggregate(r.validateSpec())
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *ApolloEnvironment) ValidateUpdate(old runtime.Object) error {
+	apolloenvironmentlog.Info("validate update", "name", r.Name)
+
+	allErrs := r.validateSpec()
+	if oldEnvironment, ok := old.(*ApolloEnvironment); ok {
+		// NOTE 修改数据库名会使config和admin连接到另一个空库
+		allErrs = append(allErrs, validateImmutable(field.NewPath("spec", "configdb", "dbName"), r.Spec.ConfigDB.DBName, oldEnvironment.Spec.ConfigDB.DBName)...)
+	}
+	return r.toAggregate(allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *ApolloEnvironment) ValidateDelete() error {
+	return nil
+}
+
+func (r *ApolloEnvironment) validateSpec() field.ErrorList {
+	spec := field.NewPath("spec")
+	allErrs := field.ErrorList{}
+	allErrs = append(allErrs, validateVersion(spec.Child("version"), r.Spec.Version)...)
+	allErrs = append(allErrs, validateDeletionPolicy(spec.Child("deletionPolicy"), r.Spec.ConfigDB.Type, r.Spec.DeletionPolicy)...)
+	allErrs = append(allErrs, validateNetworkPolicy(spec.Child("networkPolicy"), r.Spec.NetworkPolicy)...)
+	allErrs = append(allErrs, validateAutoscaling(spec.Child("configService", "autoscaling"), r.Spec.ConfigService.Autoscaling)...)
+	allErrs = append(allErrs, validatePodDisruptionBudget(spec.Child("configService", "podDisruptionBudget"), r.Spec.ConfigService.PodDisruptionBudget)...)
+	allErrs = append(allErrs, validateAutoscaling(spec.Child("adminService", "autoscaling"), r.Spec.AdminService.Autoscaling)...)
+	allErrs = append(allErrs, validatePodDisruptionBudget(spec.Child("adminService", "podDisruptionBudget"), r.Spec.AdminService.PodDisruptionBudget)...)
+	allErrs = append(allErrs, validateIngress(spec.Child("configService", "ingress"), r.Spec.ConfigService.Ingress)...)
+	allErrs = append(allErrs, validateExposure(spec.Child("configService", "exposure"), r.Spec.ConfigService.Exposure, r.Spec.ConfigService.Ingress)...)
+	allErrs = append(allErrs, validateIngress(spec.Child("adminService", "ingress"), r.Spec.AdminService.Ingress)...)
+	allErrs = append(allErrs, validateExposure(spec.Child("adminService", "exposure"), r.Spec.AdminService.Exposure, r.Spec.AdminService.Ingress)...)
+	allErrs = append(allErrs, validateContextPath(spec.Child("configService", "config", "contextPath"), r.Spec.ConfigService.Config.ContextPath)...)
+	allErrs = append(allErrs, validateContextPath(spec.Child("adminService", "config", "contextPath"), r.Spec.AdminService.Config.ContextPath)...)
+	allErrs = append(allErrs, validateServiceAccount(spec.Child("configService", "serviceAccount"), r.Spec.ConfigService.ServiceAccount)...)
+	allErrs = append(allErrs, validateServiceAccount(spec.Child("adminService", "serviceAccount"), r.Spec.AdminService.ServiceAccount)...)
+	allErrs = append(allErrs, validateExternalName(spec.Child("configdb"), r.Spec.ConfigDB.Service.Type, r.Spec.ConfigDB.Host)...)
+	allErrs = append(allErrs, validateSchema(spec.Child("configdb"), r.Spec.ConfigDB.Type, r.Spec.ConfigDB.Schema)...)
+	return allErrs
+}
+
+func (r *ApolloEnvironment) toAggregate(allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return k8serrors.NewInvalid(GroupVersion.WithKind("ApolloEnvironment").GroupKind(), r.Name, allErrs)
+}
@@ -31,6 +31,21 @@ type ApolloPortalSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
+	// Version is the apollo version of the portal, it replaces the tag of the image.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// DeletionPolicy decides what happens to the resources which cannot be owned by the ApolloPortal when it is deleted.
+	// Snapshot backs up the ApolloPortalDB with an ApolloBackup first.
+	// +optional
+	// +kubebuilder:validation:Enum=Delete;Retain;Snapshot
+	// +kubebuilder:default=Delete
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty" default:"Delete"`
+
+	// NetworkPolicy isolates the pods of the ApolloPortal with NetworkPolicies.
+	// +optional
+	NetworkPolicy NetworkPolicy `json:"networkPolicy,omitempty"`
+
 	Image string `json:"image,omitempty" default:"apolloconfig/apollo-portal:2.1.0"`
 
 	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
@@ -39,10 +54,23 @@ type ApolloPortalSpec struct {
 
 	Replicas int32 `json:"replicas,omitempty" default:"1"`
 
-	ContainerPort int32 `json:"containerPort,omitempty"`
+	// Autoscaling scales the Deployment with a HorizontalPodAutoscaler instead of Replicas.
+	// +optional
+	Autoscaling Autoscaling `json:"autoscaling,omitempty"`
+
+	// PodDisruptionBudget limits the pods evicted at once by a node drain.
+	// +optional
+	PodDisruptionBudget PodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
+
+	ContainerPort int32 `json:"containerPort,omitempty" default:"8070"`
 
 	Strategy appv1.DeploymentStrategy `json:"strategy,omitempty"`
 
+	// DisableConfigRollout keeps the pods running when the content of their ConfigMaps or Secrets changes,
+	// by default a content change rolls the pods.
+	// +optional
+	DisableConfigRollout bool `json:"disableConfigRollout,omitempty"`
+
 	Env []corev1.EnvVar `json:"env,omitempty"`
 
 	Service Service `json:"service,omitempty"`
@@ -51,6 +79,13 @@ type ApolloPortalSpec struct {
 
 	PortalDB PortalDB `json:"portaldb,omitempty"`
 
+	// Environments lists the apollo envs managed by the portal. The meta server of every env is resolved
+	// from the referenced ApolloEnvironment, together with Config.Envs and Config.MetaServers.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Environments []PortalEnvironment `json:"environments,omitempty"`
+
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 
 	Probe Probe `json:"probe,omitempty"`
@@ -61,15 +96,27 @@ type ApolloPortalSpec struct {
 
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 
+	// PodTemplate customizes the pods of the ApolloPortal.
+	// +optional
+	PodTemplate PodTemplateOverrides `json:"podTemplate,omitempty"`
+
+	// ServiceAccount runs the pods of the ApolloPortal.
+	// +optional
+	ServiceAccount ServiceAccount `json:"serviceAccount,omitempty"`
+
 	// Ingress is used to specify how ApolloPortal is exposed.
 	// +optional
 	Ingress Ingress `json:"ingress,omitempty"`
+
+	// Exposure selects how the ApolloPortal is exposed outside of the cluster, defaults to the Ingress.
+	// +optional
+	Exposure Exposure `json:"exposure,omitempty"`
 }
 
 type Service struct {
 	Port       int32              `json:"port,omitempty"`
 	TargetPort int32              `json:"targetPort,omitempty"`
-	Type       corev1.ServiceType `json:"type,omitempty"`
+	Type       corev1.ServiceType `json:"type,omitempty" default:"ClusterIP"`
 	// TODO Follow up to see if necessary, delete if not necessary
 	SessionAffinity corev1.ServiceAffinity `json:"sessionAffinity,omitempty"`
 }
@@ -82,20 +129,61 @@ type PortalConfig struct {
 	Files       []File            `json:"file,omitempty"`
 }
 
+// PortalEnvironment binds an apollo env name to the ApolloEnvironment serving it.
+type PortalEnvironment struct {
+	// Name is the apollo env name, e.g. DEV or PRO.
+	Name string `json:"name"`
+
+	// EnvironmentRef references the ApolloEnvironment whose config service is the meta server of the env.
+	EnvironmentRef EnvironmentReference `json:"environmentRef"`
+}
+
+// EnvironmentReference references an ApolloEnvironment.
+type EnvironmentReference struct {
+	// Namespace of the ApolloEnvironment, defaults to the namespace of the ApolloPortal.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the ApolloEnvironment.
+	Name string `json:"name"`
+}
+
 type PortalDB struct {
-	Username                   string          `json:"username,omitempty"`
-	Password                   string          `json:"password,omitempty"`
+	// Type is the kind of database, it selects the JDBC url, the driver and the default port.
+	// The schema of postgresql is not managed by the operator, schema.manage is only supported for mysql.
+	// +optional
+	// +kubebuilder:validation:Enum=mysql;postgresql
+	Type DatabaseType `json:"type,omitempty" default:"mysql"`
+
+	Username string `json:"username,omitempty"`
+
+	// Password is the plaintext database password.
+	// Deprecated: use PasswordSecretRef, a plaintext password is copied into an operator managed Secret.
+	Password string `json:"password,omitempty"`
+
+	// UsernameSecretRef selects the key of a Secret holding the database username, it takes precedence over Username.
+	// +optional
+	UsernameSecretRef *corev1.SecretKeySelector `json:"usernameSecretRef,omitempty"`
+
+	// PasswordSecretRef selects the key of a Secret holding the database password, it takes precedence over Password.
+	// +optional
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+
 	Host                       string          `json:"host,omitempty"`
 	Port                       int32           `json:"port,omitempty"`
 	DBName                     string          `json:"dbName,omitempty"`
 	ConnectionStringProperties string          `json:"connectionStringProperties,omitempty"`
 	Service                    PortalDBService `json:"service,omitempty"`
+
+	// Schema configures the management of the PortalDB schema by the operator.
+	// +optional
+	Schema Schema `json:"schema,omitempty"`
 }
 
 type PortalDBService struct {
 	Name string             `json:"name,omitempty"`
 	Port int32              `json:"port,omitempty"`
-	Type corev1.ServiceType `json:"type,omitempty"`
+	Type corev1.ServiceType `json:"type,omitempty" default:"ClusterIP"`
 }
 
 type File struct {
@@ -127,16 +215,68 @@ type Ingress struct {
 	// TLS configuration.
 	// +optional
 	TLS []networkingv1.IngressTLS `json:"tls,omitempty"`
+
+	// TLSIssuerRef requests a certificate covering every host from a cert-manager Issuer or ClusterIssuer,
+	// the TLS of the ingress is then built by the operator. It cannot be set with TLS.
+	// +optional
+	TLSIssuerRef *TLSIssuerRef `json:"tlsIssuerRef,omitempty"`
+}
+
+// TLSIssuerRef references the cert-manager issuer of the certificate of an ingress, the certificate is stored
+// in the secret <ingress>-tls.
+type TLSIssuerRef struct {
+	// Name of the issuer.
+	Name string `json:"name"`
+
+	// Kind of the issuer, defaults to Issuer.
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Group of the issuer, defaults to cert-manager.io. External issuers have their own group.
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// UseAnnotations annotates the ingress so that the ingress-shim of cert-manager creates the Certificate,
+	// by default the operator creates and owns the cert-manager.io/v1 Certificate.
+	// +optional
+	UseAnnotations bool `json:"useAnnotations,omitempty"`
 }
 
 // ApolloPortalStatus defines the observed state of ApolloPortal
 type ApolloPortalStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
+
+	// ObservedGeneration is the most recent generation observed by the operator.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the ApolloPortal state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Portal is the observed state of the portal service.
+	// +optional
+	Portal ComponentStatus `json:"portal,omitempty"`
+
+	// Schema is the state of the PortalDB schema managed by the operator.
+	// +optional
+	Schema SchemaStatus `json:"schema,omitempty"`
+
+	// Version is the apollo version the components are running and upgraded to.
+	// +optional
+	Version VersionStatus `json:"version,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+//+kubebuilder:printcolumn:name="Portal",type="string",JSONPath=".status.portal.ready"
+//+kubebuilder:printcolumn:name="URL",type="string",JSONPath=".status.portal.url",priority=1
+//+kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version.current"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // ApolloPortal is the Schema for the apolloportals API
 type ApolloPortal struct {
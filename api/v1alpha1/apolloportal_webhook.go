@@ -0,0 +1,109 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// log is for logging in this package.
+var apolloportallog = logf.Log.WithName("apolloportal-resource")
+
+func (r *ApolloPortal) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-apolloconfig-com-v1alpha1-apolloportal,mutating=true,failurePolicy=fail,sideEffects=None,groups=apolloconfig.com,resources=apolloportals,verbs=create;update,versions=v1alpha1,name=mapolloportal.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &ApolloPortal{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type.
+// It is also called by the controller, so it must stay idempotent and must not log.
+func (r *ApolloPortal) Default() {
+	setDefaults(&r.Spec)
+	defaultImage(&r.Spec.Image, r.Spec.Version)
+	defaultDatabasePort(r.Spec.PortalDB.Type, &r.Spec.PortalDB.Port, &r.Spec.PortalDB.Service.Port)
+	defaultService(&r.Spec.Service, r.Spec.ContainerPort)
+	defaultProbe(&r.Spec.Probe)
+}
+
+//+kubebuilder:webhook:path=/validate-apolloconfig-com-v1alpha1-apolloportal,mutating=false,failurePolicy=fail,sideEffects=None,groups=apolloconfig.com,resources=apolloportals,verbs=create;update,versions=v1alpha1,name=vapolloportal.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &ApolloPortal{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *ApolloPortal) ValidateCreate() error {
+	apolloportallog.Info("validate create", "name", r.Name)
+
+	return r.toAggregate(r.validateSpec())
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *ApolloPortal) ValidateUpdate(old runtime.Object) error {
+	apolloportallog.Info("validate update", "name", r.Name)
+
+	allErrs := r.validateSpec()
+	if oldPortal, ok := old.(*ApolloPortal); ok {
+		// NOTE 修改数据库名会使portal连接到另一个空库
+		allErrs = append(allErrs, validateImmutable(field.NewPath("spec", "portaldb", "dbName"), r.Spec.PortalDB.DBName, oldPortal.Spec.PortalDB.DBName)...)
+	}
+	return r.toAggregate(allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *ApolloPortal) ValidateDelete() error {
+	return nil
+}
+
+func (r *ApolloPortal) validateSpec() field.ErrorList {
+	spec := field.NewPath("spec")
+	allErrs := field.ErrorList{}
+	allErrs = append(allErrs, validateVersion(spec.Child("version"), r.Spec.Version)...)
+	allErrs = append(allErrs, validateDeletionPolicy(spec.Child("deletionPolicy"), r.Spec.PortalDB.Type, r.Spec.DeletionPolicy)...)
+	allErrs = append(allErrs, validateNetworkPolicy(spec.Child("networkPolicy"), r.Spec.NetworkPolicy)...)
+	allErrs = append(allErrs, validateAutoscaling(spec.Child("autoscaling"), r.Spec.Autoscaling)...)
+	allErrs = append(allErrs, validatePodDisruptionBudget(spec.Child("podDisruptionBudget"), r.Spec.PodDisruptionBudget)...)
+	allErrs = append(allErrs, validateIngress(spec.Child("ingress"), r.Spec.Ingress)...)
+	allErrs = append(allErrs, validateExposure(spec.Child("exposure"), r.Spec.Exposure, r.Spec.Ingress)...)
+	allErrs = append(allErrs, validateContextPath(spec.Child("config", "contextPath"), r.Spec.Config.ContextPath)...)
+	allErrs = append(allErrs, validateServiceAccount(spec.Child("serviceAccount"), r.Spec.ServiceAccount)...)
+	allErrs = append(allErrs, validateExternalName(spec.Child("portaldb"), r.Spec.PortalDB.Service.Type, r.Spec.PortalDB.Host)...)
+	allErrs = append(allErrs, validateSchema(spec.Child("portaldb"), r.Spec.PortalDB.Type, r.Spec.PortalDB.Schema)...)
+	for i, env := range r.Spec.Environments {
+		if env.Name == "" {
+			allErrs = append(allErrs, field.Required(spec.Child("environments").Index(i).Child("name"), "env name is required"))
+		}
+		if env.EnvironmentRef.Name == "" {
+			allErrs = append(allErrs, field.Required(spec.Child("environments").Index(i).Child("environmentRef", "name"), "ApolloEnvironment name is required"))
+		}
+	}
+	return allErrs
+}
+
+func (r *ApolloPortal) toAggregate(allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return k8serrors.NewInvalid(GroupVersion.WithKind("ApolloPortal").GroupKind(), r.Name, allErrs)
+}
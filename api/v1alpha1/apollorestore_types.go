@@ -0,0 +1,76 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ApolloRestoreSpec defines the desired state of ApolloRestore
+type ApolloRestoreSpec struct {
+	// BackupName is the completed ApolloBackup restored into the databases of its target. The Deployments of the target
+	// are scaled to zero while the dumps are restored, and scaled back once the restore finished.
+	BackupName string `json:"backupName"`
+}
+
+// ApolloRestoreStatus defines the observed state of ApolloRestore
+type ApolloRestoreStatus struct {
+	// Phase of the restore.
+	// +optional
+	Phase BackupPhase `json:"phase,omitempty"`
+
+	// StartTime is when the restore job started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when the restore job completed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Message explains the phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Backup",type="string",JSONPath=".spec.backupName"
+//+kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+//+kubebuilder:printcolumn:name="Completed",type="date",JSONPath=".status.completionTime"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ApolloRestore is the Schema for the apollorestores API
+type ApolloRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ApolloRestoreSpec   `json:"spec,omitempty"`
+	Status ApolloRestoreStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ApolloRestoreList contains a list of ApolloRestore
+type ApolloRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ApolloRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ApolloRestore{}, &ApolloRestoreList{})
+}
@@ -0,0 +1,367 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Condition types reported in the status of every Apollo kind.
+const (
+	// ConditionReady is True when every component is available and the database is reachable.
+	ConditionReady = "Ready"
+	// ConditionProgressing is True while a component is being created or rolled out.
+	ConditionProgressing = "Progressing"
+	// ConditionDegraded is True when a component failed to roll out or the database is unreachable.
+	ConditionDegraded = "Degraded"
+	// ConditionDatabaseReachable is True when the operator could reach the backing database.
+	ConditionDatabaseReachable = "DatabaseReachable"
+	// ConditionSchemaReady is True when the managed database schema matches the version of the images.
+	ConditionSchemaReady = "SchemaReady"
+)
+
+// Condition reasons reported in the status of every Apollo kind.
+const (
+	ReasonComponentsReady     = "ComponentsReady"
+	ReasonComponentsNotReady  = "ComponentsNotReady"
+	ReasonRolloutInProgress   = "RolloutInProgress"
+	ReasonRolloutComplete     = "RolloutComplete"
+	ReasonRolloutFailed       = "RolloutFailed"
+	ReasonAsExpected          = "AsExpected"
+	ReasonDatabaseReachable   = "DatabaseReachable"
+	ReasonDatabaseUnreachable = "DatabaseUnreachable"
+	ReasonSchemaApplied       = "SchemaApplied"
+	ReasonSchemaPending       = "SchemaPending"
+	ReasonSchemaFailed        = "SchemaFailed"
+)
+
+// DatabaseType is the kind of a database used by apollo.
+type DatabaseType string
+
+const (
+	DatabaseTypeMySQL      DatabaseType = "mysql"
+	DatabaseTypePostgreSQL DatabaseType = "postgresql"
+)
+
+// DefaultImage returns the image of the database deployed by the operator, its client also runs the schema jobs.
+func (t DatabaseType) DefaultImage() string {
+	if t == DatabaseTypePostgreSQL {
+		return "postgres:14"
+	}
+	return "mysql:5.7"
+}
+
+// DefaultPort returns the port the database listens on by default.
+func (t DatabaseType) DefaultPort() int32 {
+	if t == DatabaseTypePostgreSQL {
+		return 5432
+	}
+	return 3306
+}
+
+// DeletionPolicy decides what happens to the data and the resources which are not owned by an Apollo kind when it is deleted.
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes them.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyRetain keeps them, e.g. the PVC of the all-in-one database is reused by a new Apollo of the same name.
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+	// DeletionPolicySnapshot backs up the databases with an ApolloBackup, which is kept, then deletes them.
+	DeletionPolicySnapshot DeletionPolicy = "Snapshot"
+)
+
+// Autoscaling configures an autoscaling/v2 HorizontalPodAutoscaler for the Deployment of a component.
+type Autoscaling struct {
+	// Enabled creates the HorizontalPodAutoscaler, the replicas of the Deployment are then left to it
+	// and the replicas of the component are ignored.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// MinReplicas is the lower limit of the replicas, defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the upper limit of the replicas, it cannot be less than MinReplicas.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas,omitempty"`
+
+	// TargetCPUUtilizationPercentage is the average CPU utilization of the pods, in percent of their CPU requests.
+	// It defaults to 80 when no other metric is set.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+
+	// TargetMemoryUtilizationPercentage is the average memory utilization of the pods, in percent of their memory requests.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TargetMemoryUtilizationPercentage *int32 `json:"targetMemoryUtilizationPercentage,omitempty"`
+
+	// Metrics are added to the CPU and memory metrics, e.g. a pods metric of the long polling clients of the config service.
+	// +optional
+	Metrics []autoscalingv2.MetricSpec `json:"metrics,omitempty"`
+
+	// Behavior configures the scaling up and down, the defaults of the HorizontalPodAutoscaler are used when empty.
+	// +optional
+	Behavior *autoscalingv2.HorizontalPodAutoscalerBehavior `json:"behavior,omitempty"`
+}
+
+// PodDisruptionBudget configures the policy/v1 PodDisruptionBudget of the pods of a component.
+type PodDisruptionBudget struct {
+	// Enabled creates the PodDisruptionBudget. By default it is created when the component runs more than one replica,
+	// or when its autoscaling allows more than one, so that a node drain cannot evict every pod at once.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// MinAvailable is the number or the percentage of the pods which must stay available, it cannot be set with MaxUnavailable.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// MaxUnavailable is the number or the percentage of the pods which can be evicted at once, it defaults to 1
+	// when MinAvailable is not set.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// NetworkPolicy configures the networking.k8s.io/v1 NetworkPolicies isolating the pods of an Apollo kind.
+type NetworkPolicy struct {
+	// Enabled creates the NetworkPolicies. The database of the all-in-one kind only accepts its config, admin and portal
+	// services, the jobs and the operator. The admin service only accepts the portals, the config service only accepts
+	// the clients and the portals, and the ApolloPortal only accepts the clients. The portal of the all-in-one kind is not isolated.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// ClientNamespaceSelectors select the namespaces allowed to call the config service, or the ApolloPortal,
+	// in addition to the namespace of the instance. Every namespace is allowed when empty.
+	// +optional
+	ClientNamespaceSelectors []metav1.LabelSelector `json:"clientNamespaceSelectors,omitempty"`
+
+	// PortalNamespaceSelectors select the namespaces of the ApolloPortals allowed to call the admin service and the meta server
+	// of the config service, in addition to the namespace of the instance. It is not used by the ApolloPortal.
+	// +optional
+	PortalNamespaceSelectors []metav1.LabelSelector `json:"portalNamespaceSelectors,omitempty"`
+}
+
+// ExposureType is the kind of the object exposing a component outside of the cluster.
+type ExposureType string
+
+const (
+	// ExposureTypeIngress exposes the component with a networking.k8s.io/v1 Ingress.
+	ExposureTypeIngress ExposureType = "ingress"
+	// ExposureTypeGatewayAPI exposes the component with a gateway.networking.k8s.io/v1 HTTPRoute.
+	ExposureTypeGatewayAPI ExposureType = "gatewayAPI"
+	// ExposureTypeRoute exposes the component with route.openshift.io/v1 Routes.
+	ExposureTypeRoute ExposureType = "route"
+	// ExposureTypeNone does not expose the component.
+	ExposureTypeNone ExposureType = "none"
+)
+
+// Exposure selects how a component is exposed, every type serves the hosts of the ingress of the component.
+type Exposure struct {
+	// Type of the object exposing the component, defaults to ingress. The objects of the other types are deleted.
+	// +optional
+	// +kubebuilder:validation:Enum=ingress;gatewayAPI;route;none
+	Type ExposureType `json:"type,omitempty" default:"ingress"`
+
+	// GatewayAPI configures the HTTPRoute of the gatewayAPI type.
+	// +optional
+	GatewayAPI GatewayAPIExposure `json:"gatewayAPI,omitempty"`
+
+	// Route configures the OpenShift Routes of the route type.
+	// +optional
+	Route RouteExposure `json:"route,omitempty"`
+}
+
+// GatewayAPIExposure configures the HTTPRoute attaching a component to Gateways.
+type GatewayAPIExposure struct {
+	// ParentRefs are the Gateways the HTTPRoute attaches to. TLS is terminated by the listeners of the Gateways, sectionName
+	// selects a HTTPS listener, which can use the certificate requested by the tlsIssuerRef of the ingress.
+	// +optional
+	ParentRefs []GatewayParentRef `json:"parentRefs,omitempty"`
+}
+
+// RouteTermination is the TLS termination of an OpenShift Route.
+type RouteTermination string
+
+const (
+	// RouteTerminationEdge terminates TLS at the router, which forwards plain HTTP to the pods.
+	RouteTerminationEdge RouteTermination = "edge"
+	// RouteTerminationPassthrough forwards the TLS connections to the pods.
+	RouteTerminationPassthrough RouteTermination = "passthrough"
+	// RouteTerminationReencrypt terminates TLS at the router, which opens another TLS connection to the pods.
+	RouteTerminationReencrypt RouteTermination = "reencrypt"
+)
+
+// RouteExposure configures the OpenShift Routes exposing a component, one Route per host of the ingress since a Route
+// has a single host. The router picks a host when the ingress has none.
+type RouteExposure struct {
+	// Termination of TLS, the Routes serve plain HTTP when empty. The edge and reencrypt terminations use the secret
+	// of the ingress tls covering the host, or the certificate requested by tlsIssuerRef, as external certificate;
+	// the router must be allowed to read it.
+	// +optional
+	// +kubebuilder:validation:Enum=edge;passthrough;reencrypt
+	Termination RouteTermination `json:"termination,omitempty"`
+
+	// InsecureEdgeTerminationPolicy handles the plain HTTP requests of a TLS Route, one of None, Allow or Redirect.
+	// +optional
+	// +kubebuilder:validation:Enum=None;Allow;Redirect
+	InsecureEdgeTerminationPolicy string `json:"insecureEdgeTerminationPolicy,omitempty"`
+
+	// DestinationCACertificate is the PEM encoded CA validating the certificate of the pods with the reencrypt termination.
+	// +optional
+	DestinationCACertificate string `json:"destinationCACertificate,omitempty"`
+
+	// Annotations are added to the Routes, e.g. haproxy.router.openshift.io/timeout.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// GatewayParentRef references a Gateway or one of its listeners.
+type GatewayParentRef struct {
+	// Name of the Gateway.
+	Name string `json:"name"`
+
+	// Namespace of the Gateway, defaults to the namespace of the instance.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// SectionName is the name of a listener of the Gateway, the HTTPRoute attaches to every listener allowing it when empty.
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+// PodTemplateOverrides customizes the pods of a component. The pods comply with the restricted Pod Security Standard
+// by default: they run as non-root with the RuntimeDefault seccomp profile, without privilege escalation and capabilities.
+type PodTemplateOverrides struct {
+	// SecurityContext of the pods. runAsNonRoot and seccompProfile are set when missing; the user, group and fsGroup
+	// of the image are only set by default when securityContext is empty, e.g. to let OpenShift assign them.
+	// +optional
+	SecurityContext *corev1.PodSecurityContext `json:"securityContext,omitempty"`
+
+	// ContainerSecurityContext of the containers of the pods, allowPrivilegeEscalation and capabilities are set when missing.
+	// +optional
+	ContainerSecurityContext *corev1.SecurityContext `json:"containerSecurityContext,omitempty"`
+
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// +optional
+	RuntimeClassName *string `json:"runtimeClassName,omitempty"`
+
+	// PodAnnotations are added to the pods, the annotations set by the operator win.
+	// +optional
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+
+	// PodLabels are added to the pods, the selector labels win.
+	// +optional
+	PodLabels map[string]string `json:"podLabels,omitempty"`
+
+	// +optional
+	HostAliases []corev1.HostAlias `json:"hostAliases,omitempty"`
+
+	// +optional
+	DNSConfig *corev1.PodDNSConfig `json:"dnsConfig,omitempty"`
+}
+
+// ServiceAccount selects the ServiceAccount running the pods of a component. The operator creates one per component
+// unless name points to an existing account.
+type ServiceAccount struct {
+	// Name of an existing ServiceAccount, the operator does not create one when it is set.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Annotations of the created ServiceAccount, e.g. eks.amazonaws.com/role-arn or iam.gke.io/gcp-service-account
+	// to bind a cloud workload identity.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// AutomountServiceAccountToken mounts the token of the ServiceAccount into the pods, defaults to false
+	// since Apollo does not call the Kubernetes API.
+	// +optional
+	AutomountServiceAccountToken bool `json:"automountServiceAccountToken,omitempty"`
+}
+
+// ComponentStatus describes the observed state of a single Apollo component (config, admin or portal service).
+type ComponentStatus struct {
+	// Replicas is the number of replicas desired for the component.
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// ReadyReplicas is the number of ready replicas of the component.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// Ready is a "ready/desired" summary of the replicas, used by printer columns.
+	// +optional
+	Ready string `json:"ready,omitempty"`
+
+	// URL is the in-cluster address of the component's Service.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// Certificate is the state of the cert-manager certificate of the component's Ingress.
+	// +optional
+	Certificate *CertificateStatus `json:"certificate,omitempty"`
+}
+
+// CertificateStatus describes a cert-manager Certificate requested for an Ingress.
+type CertificateStatus struct {
+	// Name of the Certificate, it is also the name of the secret holding the certificate.
+	Name string `json:"name"`
+
+	// Ready is true when the certificate is issued and up to date.
+	Ready bool `json:"ready"`
+
+	// Message explains why the certificate is not ready.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// Schema configures the management of a database schema by the operator.
+type Schema struct {
+	// Manage runs a Job applying the schema scripts embedded in the operator for the version of the images,
+	// the Deployments are not rolled out until the Job succeeded. Only mysql scripts are embedded, it cannot be set
+	// with the postgresql type, whose schema has to be created and migrated by hand.
+	// +optional
+	Manage bool `json:"manage,omitempty"`
+}
+
+// SchemaStatus describes the schema applied by the operator.
+type SchemaStatus struct {
+	// Version is the schema version applied by the last successful schema Job.
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+// VersionStatus describes the apollo version of the components.
+type VersionStatus struct {
+	// Current is the version every component is rolled out to.
+	// +optional
+	Current string `json:"current,omitempty"`
+
+	// Target is the version the components are being upgraded to.
+	// +optional
+	Target string `json:"target,omitempty"`
+}
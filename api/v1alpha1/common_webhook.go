@@ -0,0 +1,295 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Probe timings applied when the user leaves them empty.
+const (
+	defaultLivenessInitialDelaySeconds  = 100
+	defaultLivenessPeriodSeconds        = 10
+	defaultReadinessInitialDelaySeconds = 30
+	defaultReadinessPeriodSeconds       = 5
+)
+
+// versionPattern matches a valid image tag.
+var versionPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+
+// apiPkgPath is used to only descend into the structs defined in this package when applying defaults.
+var apiPkgPath = reflect.TypeOf(Service{}).PkgPath()
+
+// setDefaults walks the struct pointed to by p and sets the value of the `default` tag
+// on every string or integer field that is still empty. Nested structs of this package are visited recursively.
+func setDefaults(p any) {
+	v := reflect.ValueOf(p).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+
+		if field.Type.Kind() == reflect.Struct && field.Type.PkgPath() == apiPkgPath {
+			setDefaults(fv.Addr().Interface())
+			continue
+		}
+
+		value, ok := field.Tag.Lookup("default")
+		if !ok {
+			continue
+		}
+		switch field.Type.Kind() {
+		case reflect.String:
+			if fv.String() == "" {
+				fv.SetString(value)
+			}
+		case reflect.Int, reflect.Int32, reflect.Int64:
+			if fv.Int() == 0 {
+				if intValue, err := strconv.ParseInt(value, 10, 64); err == nil {
+					fv.SetInt(intValue)
+				}
+			}
+		}
+	}
+}
+
+// defaultImage replaces the tag of image by version, a digest pins the image and is kept.
+func defaultImage(image *string, version string) {
+	if version == "" || strings.Contains(*image, "@") {
+		return
+	}
+	repository := *image
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository = repository[:i]
+	}
+	*image = repository + ":" + version
+}
+
+// defaultService points the service at the container port when no port is given.
+func defaultService(service *Service, containerPort int32) {
+	if service.Port == 0 {
+		service.Port = containerPort
+	}
+	if service.TargetPort == 0 {
+		service.TargetPort = containerPort
+	}
+}
+
+// defaultDatabasePort sets the default port of the database type on the database and on its service.
+func defaultDatabasePort(dbType DatabaseType, port, servicePort *int32) {
+	if *port == 0 {
+		*port = dbType.DefaultPort()
+	}
+	if *servicePort == 0 {
+		*servicePort = *port
+	}
+}
+
+// defaultProbe fills the probe timings, the probe handlers are always built by the operator.
+func defaultProbe(probe *Probe) {
+	if probe.Liveness.InitialDelaySeconds == 0 {
+		probe.Liveness.InitialDelaySeconds = defaultLivenessInitialDelaySeconds
+	}
+	if probe.Liveness.PeriodSeconds == 0 {
+		probe.Liveness.PeriodSeconds = defaultLivenessPeriodSeconds
+	}
+	if probe.Readineeds.InitialDelaySeconds == 0 {
+		probe.Readineeds.InitialDelaySeconds = defaultReadinessInitialDelaySeconds
+	}
+	if probe.Readineeds.PeriodSeconds == 0 {
+		probe.Readineeds.PeriodSeconds = defaultReadinessPeriodSeconds
+	}
+}
+
+// validateIngress checks that every TLS host is also served by the ingress, and that a certificate can be requested for the hosts.
+func validateIngress(path *field.Path, ingress Ingress) field.ErrorList {
+	allErrs := field.ErrorList{}
+	hosts := make(map[string]bool, len(ingress.Hosts))
+	for _, host := range ingress.Hosts {
+		hosts[host] = true
+	}
+	if ingress.TLSIssuerRef != nil {
+		if len(ingress.TLS) > 0 {
+			allErrs = append(allErrs, field.Forbidden(path.Child("tlsIssuerRef"), "tls and tlsIssuerRef cannot be both set"))
+		}
+		if len(ingress.Hosts) == 0 {
+			allErrs = append(allErrs, field.Required(path.Child("hosts"), "hosts are required to request a certificate"))
+		}
+		if ingress.TLSIssuerRef.Name == "" {
+			allErrs = append(allErrs, field.Required(path.Child("tlsIssuerRef", "name"), "name of the issuer is required"))
+		}
+	}
+	for i, tls := range ingress.TLS {
+		for j, host := range tls.Hosts {
+			if !hosts[host] {
+				allErrs = append(allErrs, field.Invalid(path.Child("tls").Index(i).Child("hosts").Index(j), host,
+					"must be one of "+path.Child("hosts").String()))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateContextPath checks that a context path is either empty or an absolute path without a trailing slash,
+// the operator appends "/health" and the like to it.
+func validateContextPath(path *field.Path, contextPath string) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if contextPath == "" {
+		return allErrs
+	}
+	if !strings.HasPrefix(contextPath, "/") {
+		allErrs = append(allErrs, field.Invalid(path, contextPath, "must start with '/'"))
+	} else if strings.HasSuffix(contextPath, "/") {
+		allErrs = append(allErrs, field.Invalid(path, contextPath, "must not end with '/'"))
+	}
+	return allErrs
+}
+
+// validateExternalName checks that an ExternalName database service has a host to point to.
+func validateExternalName(path *field.Path, serviceType corev1.ServiceType, host string) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if serviceType == corev1.ServiceTypeExternalName && host == "" {
+		allErrs = append(allErrs, field.Required(path.Child("host"), "host is required when service.type is ExternalName"))
+	}
+	return allErrs
+}
+
+// validateVersion checks that the version can be used as an image tag.
+func validateVersion(path *field.Path, version string) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if version != "" && !versionPattern.MatchString(version) {
+		allErrs = append(allErrs, field.Invalid(path, version, "must be a valid image tag, e.g. 2.1.0"))
+	}
+	return allErrs
+}
+
+// validateSchema checks that the managed schema is supported by the database type, the embedded scripts are written for mysql.
+func validateSchema(path *field.Path, dbType DatabaseType, schema Schema) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if schema.Manage && dbType != DatabaseTypeMySQL {
+		allErrs = append(allErrs, field.Invalid(path.Child("schema", "manage"), schema.Manage, "schema management is only supported for mysql"))
+	}
+	return allErrs
+}
+
+// validateDeletionPolicy checks that the databases can be snapshotted, the backups are taken with mysqldump.
+func validateDeletionPolicy(path *field.Path, dbType DatabaseType, policy DeletionPolicy) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if policy == DeletionPolicySnapshot && dbType == DatabaseTypePostgreSQL {
+		allErrs = append(allErrs, field.Invalid(path, policy, "Snapshot is only supported for mysql"))
+	}
+	return allErrs
+}
+
+// validateAutoscaling checks the replica limits of an enabled HorizontalPodAutoscaler.
+func validateAutoscaling(path *field.Path, autoscaling Autoscaling) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !autoscaling.Enabled {
+		return allErrs
+	}
+	if autoscaling.MaxReplicas == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("maxReplicas"), "maxReplicas is required when autoscaling is enabled"))
+	} else if autoscaling.MinReplicas != nil && *autoscaling.MinReplicas > autoscaling.MaxReplicas {
+		allErrs = append(allErrs, field.Invalid(path.Child("maxReplicas"), autoscaling.MaxReplicas, "must be greater than or equal to minReplicas"))
+	}
+	return allErrs
+}
+
+// validatePodDisruptionBudget checks that only one of the limits of a PodDisruptionBudget is set.
+func validatePodDisruptionBudget(path *field.Path, pdb PodDisruptionBudget) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if pdb.MinAvailable != nil && pdb.MaxUnavailable != nil {
+		allErrs = append(allErrs, field.Forbidden(path.Child("maxUnavailable"), "minAvailable and maxUnavailable cannot be both set"))
+	}
+	return allErrs
+}
+
+// validateNetworkPolicy checks the namespace selectors of the NetworkPolicies.
+func validateNetworkPolicy(path *field.Path, policy NetworkPolicy) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i := range policy.ClientNamespaceSelectors {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(&policy.ClientNamespaceSelectors[i], path.Child("clientNamespaceSelectors").Index(i))...)
+	}
+	for i := range policy.PortalNamespaceSelectors {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(&policy.PortalNamespaceSelectors[i], path.Child("portalNamespaceSelectors").Index(i))...)
+	}
+	return allErrs
+}
+
+// validateExposure checks that an HTTPRoute has a Gateway to attach to, that the TLS options of the Routes match
+// their termination, and that the annotations of cert-manager are
+// only used with an Ingress, the other types get their certificate from a Certificate object.
+func validateExposure(path *field.Path, exposure Exposure, ingress Ingress) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if exposure.Type != ExposureTypeIngress && ingress.TLSIssuerRef != nil && ingress.TLSIssuerRef.UseAnnotations {
+		allErrs = append(allErrs, field.Invalid(path.Child("type"), exposure.Type, "ingress.tlsIssuerRef.useAnnotations requires the ingress type"))
+	}
+	if exposure.Type == ExposureTypeGatewayAPI && len(exposure.GatewayAPI.ParentRefs) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("gatewayAPI", "parentRefs"), "a gateway is required when type is gatewayAPI"))
+	}
+	for i, ref := range exposure.GatewayAPI.ParentRefs {
+		if ref.Name == "" {
+			allErrs = append(allErrs, field.Required(path.Child("gatewayAPI", "parentRefs").Index(i).Child("name"), "name of the gateway is required"))
+		}
+	}
+
+	route := exposure.Route
+	if route.Termination == "" && route.InsecureEdgeTerminationPolicy != "" {
+		allErrs = append(allErrs, field.Forbidden(path.Child("route", "insecureEdgeTerminationPolicy"), "only allowed with a termination"))
+	}
+	if route.Termination == RouteTerminationPassthrough && route.InsecureEdgeTerminationPolicy == "Allow" {
+		allErrs = append(allErrs, field.Invalid(path.Child("route", "insecureEdgeTerminationPolicy"), route.InsecureEdgeTerminationPolicy, "must be None or Redirect with the passthrough termination"))
+	}
+	if route.Termination != RouteTerminationReencrypt && route.DestinationCACertificate != "" {
+		allErrs = append(allErrs, field.Forbidden(path.Child("route", "destinationCACertificate"), "only allowed with the reencrypt termination"))
+	}
+	return allErrs
+}
+
+// validateServiceAccount checks the name of an existing ServiceAccount and the annotations of a created one.
+func validateServiceAccount(path *field.Path, serviceAccount ServiceAccount) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if serviceAccount.Name != "" {
+		for _, msg := range apimachineryvalidation.NameIsDNSSubdomain(serviceAccount.Name, false) {
+			allErrs = append(allErrs, field.Invalid(path.Child("name"), serviceAccount.Name, msg))
+		}
+		if len(serviceAccount.Annotations) > 0 {
+			allErrs = append(allErrs, field.Forbidden(path.Child("annotations"), "the annotations of an existing service account are not managed"))
+		}
+	}
+	allErrs = append(allErrs, apimachineryvalidation.ValidateAnnotations(serviceAccount.Annotations, path.Child("annotations"))...)
+	return allErrs
+}
+
+// validateImmutable rejects a change of a field that cannot be updated after creation.
+func validateImmutable(path *field.Path, newValue, oldValue any) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !reflect.DeepEqual(newValue, oldValue) {
+		allErrs = append(allErrs, field.Invalid(path, newValue, "field is immutable"))
+	}
+	return allErrs
+}
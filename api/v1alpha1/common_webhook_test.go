@@ -0,0 +1,225 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"reflect"
+	"testing"
+)
+
+var specPath = field.NewPath("spec")
+
+// errorFields returns the fields of errs, which the tests compare instead of the messages.
+func errorFields(errs field.ErrorList) []string {
+	fields := []string{}
+	for _, err := range errs {
+		fields = append(fields, err.Field)
+	}
+	return fields
+}
+
+func checkErrorFields(t *testing.T, errs field.ErrorList, want []string) {
+	t.Helper()
+	if want == nil {
+		want = []string{}
+	}
+	if got := errorFields(errs); !reflect.DeepEqual(got, want) {
+		t.Errorf("error fields = %v, want %v (errors %v)", got, want, errs)
+	}
+}
+
+func TestValidateAutoscaling(t *testing.T) {
+	three := int32(3)
+	tests := []struct {
+		name        string
+		autoscaling Autoscaling
+		want        []string
+	}{
+		{name: "disabled", autoscaling: Autoscaling{MinReplicas: &three}},
+		{name: "enabled", autoscaling: Autoscaling{Enabled: true, MinReplicas: &three, MaxReplicas: 3}},
+		{name: "without minReplicas", autoscaling: Autoscaling{Enabled: true, MaxReplicas: 1}},
+		{name: "without maxReplicas", autoscaling: Autoscaling{Enabled: true}, want: []string{"spec.maxReplicas"}},
+		{name: "maxReplicas below minReplicas", autoscaling: Autoscaling{Enabled: true, MinReplicas: &three, MaxReplicas: 2}, want: []string{"spec.maxReplicas"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkErrorFields(t, validateAutoscaling(specPath, tt.autoscaling), tt.want)
+		})
+	}
+}
+
+func TestValidatePodDisruptionBudget(t *testing.T) {
+	one := intstr.FromInt(1)
+	tests := []struct {
+		name string
+		pdb  PodDisruptionBudget
+		want []string
+	}{
+		{name: "defaults"},
+		{name: "minAvailable", pdb: PodDisruptionBudget{MinAvailable: &one}},
+		{name: "maxUnavailable", pdb: PodDisruptionBudget{MaxUnavailable: &one}},
+		{name: "both limits", pdb: PodDisruptionBudget{MinAvailable: &one, MaxUnavailable: &one}, want: []string{"spec.maxUnavailable"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkErrorFields(t, validatePodDisruptionBudget(specPath, tt.pdb), tt.want)
+		})
+	}
+}
+
+func TestValidateNetworkPolicy(t *testing.T) {
+	valid := metav1.LabelSelector{MatchLabels: map[string]string{"team": "apollo"}}
+	invalid := metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Contains"}}}
+	tests := []struct {
+		name   string
+		policy NetworkPolicy
+		want   []string
+	}{
+		{name: "without selectors"},
+		{name: "valid selectors", policy: NetworkPolicy{ClientNamespaceSelectors: []metav1.LabelSelector{valid}, PortalNamespaceSelectors: []metav1.LabelSelector{valid}}},
+		{
+			name:   "invalid selectors",
+			policy: NetworkPolicy{ClientNamespaceSelectors: []metav1.LabelSelector{valid, invalid}, PortalNamespaceSelectors: []metav1.LabelSelector{invalid}},
+			want: []string{
+				"spec.clientNamespaceSelectors[1].matchExpressions[0].operator",
+				"spec.portalNamespaceSelectors[0].matchExpressions[0].operator",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkErrorFields(t, validateNetworkPolicy(specPath, tt.policy), tt.want)
+		})
+	}
+}
+
+func TestValidateIngress(t *testing.T) {
+	tests := []struct {
+		name    string
+		ingress Ingress
+		want    []string
+	}{
+		{name: "without hosts"},
+		{name: "tls hosts served", ingress: Ingress{Hosts: []string{"apollo.example.com"}, TLS: []networkingv1.IngressTLS{{Hosts: []string{"apollo.example.com"}}}}},
+		{
+			name:    "tls host not served",
+			ingress: Ingress{Hosts: []string{"apollo.example.com"}, TLS: []networkingv1.IngressTLS{{Hosts: []string{"apollo.example.com", "other.example.com"}}}},
+			want:    []string{"spec.tls[0].hosts[1]"},
+		},
+		{name: "issuer", ingress: Ingress{Hosts: []string{"apollo.example.com"}, TLSIssuerRef: &TLSIssuerRef{Name: "letsencrypt"}}},
+		{
+			name:    "issuer with tls",
+			ingress: Ingress{Hosts: []string{"apollo.example.com"}, TLS: []networkingv1.IngressTLS{{Hosts: []string{"apollo.example.com"}}}, TLSIssuerRef: &TLSIssuerRef{Name: "letsencrypt"}},
+			want:    []string{"spec.tlsIssuerRef"},
+		},
+		{name: "issuer without hosts and name", ingress: Ingress{TLSIssuerRef: &TLSIssuerRef{}}, want: []string{"spec.hosts", "spec.tlsIssuerRef.name"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkErrorFields(t, validateIngress(specPath, tt.ingress), tt.want)
+		})
+	}
+}
+
+func TestValidateExposure(t *testing.T) {
+	annotations := Ingress{TLSIssuerRef: &TLSIssuerRef{Name: "letsencrypt", UseAnnotations: true}}
+	tests := []struct {
+		name     string
+		exposure Exposure
+		ingress  Ingress
+		want     []string
+	}{
+		{name: "ingress", exposure: Exposure{Type: ExposureTypeIngress}, ingress: annotations},
+		{name: "issuer annotations without an ingress", exposure: Exposure{Type: ExposureTypeRoute}, ingress: annotations, want: []string{"spec.type"}},
+		{name: "gateway", exposure: Exposure{Type: ExposureTypeGatewayAPI, GatewayAPI: GatewayAPIExposure{ParentRefs: []GatewayParentRef{{Name: "gateway"}}}}},
+		{name: "gateway without parentRefs", exposure: Exposure{Type: ExposureTypeGatewayAPI}, want: []string{"spec.gatewayAPI.parentRefs"}},
+		{
+			name:     "parentRef without name",
+			exposure: Exposure{Type: ExposureTypeGatewayAPI, GatewayAPI: GatewayAPIExposure{ParentRefs: []GatewayParentRef{{Name: "gateway"}, {}}}},
+			want:     []string{"spec.gatewayAPI.parentRefs[1].name"},
+		},
+		{name: "edge route", exposure: Exposure{Type: ExposureTypeRoute, Route: RouteExposure{Termination: RouteTerminationEdge, InsecureEdgeTerminationPolicy: "Allow"}}},
+		{
+			name:     "insecure policy without termination",
+			exposure: Exposure{Type: ExposureTypeRoute, Route: RouteExposure{InsecureEdgeTerminationPolicy: "Redirect"}},
+			want:     []string{"spec.route.insecureEdgeTerminationPolicy"},
+		},
+		{
+			name:     "passthrough allowing plain http",
+			exposure: Exposure{Type: ExposureTypeRoute, Route: RouteExposure{Termination: RouteTerminationPassthrough, InsecureEdgeTerminationPolicy: "Allow"}},
+			want:     []string{"spec.route.insecureEdgeTerminationPolicy"},
+		},
+		{name: "reencrypt with a CA", exposure: Exposure{Type: ExposureTypeRoute, Route: RouteExposure{Termination: RouteTerminationReencrypt, DestinationCACertificate: "ca"}}},
+		{
+			name:     "edge with a CA",
+			exposure: Exposure{Type: ExposureTypeRoute, Route: RouteExposure{Termination: RouteTerminationEdge, DestinationCACertificate: "ca"}},
+			want:     []string{"spec.route.destinationCACertificate"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkErrorFields(t, validateExposure(specPath, tt.exposure, tt.ingress), tt.want)
+		})
+	}
+}
+
+func TestValidateServiceAccount(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceAccount ServiceAccount
+		want           []string
+	}{
+		{name: "created"},
+		{name: "created with annotations", serviceAccount: ServiceAccount{Annotations: map[string]string{"eks.amazonaws.com/role-arn": "arn"}}},
+		{name: "existing", serviceAccount: ServiceAccount{Name: "apollo"}},
+		{name: "invalid name", serviceAccount: ServiceAccount{Name: "Apollo_SA"}, want: []string{"spec.name"}},
+		{
+			name:           "existing with annotations",
+			serviceAccount: ServiceAccount{Name: "apollo", Annotations: map[string]string{"eks.amazonaws.com/role-arn": "arn"}},
+			want:           []string{"spec.annotations"},
+		},
+		{name: "invalid annotation", serviceAccount: ServiceAccount{Annotations: map[string]string{"not valid": "x"}}, want: []string{"spec.annotations"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkErrorFields(t, validateServiceAccount(specPath, tt.serviceAccount), tt.want)
+		})
+	}
+}
+
+func TestValidateContextPath(t *testing.T) {
+	tests := []struct {
+		name        string
+		contextPath string
+		want        []string
+	}{
+		{name: "empty"},
+		{name: "absolute", contextPath: "/apollo"},
+		{name: "relative", contextPath: "apollo", want: []string{"spec"}},
+		{name: "trailing slash", contextPath: "/apollo/", want: []string{"spec"}},
+		{name: "root", contextPath: "/", want: []string{"spec"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkErrorFields(t, validateContextPath(specPath, tt.contextPath), tt.want)
+		})
+	}
+}
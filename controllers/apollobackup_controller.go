@@ -0,0 +1,138 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"apolloconfig.com/apollo-operator/pkg/reconcile/backup"
+	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"context"
+	"fmt"
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
+
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+)
+
+// ApolloBackupReconciler reconciles a ApolloBackup object
+type ApolloBackupReconciler struct {
+	client.Client
+	recorder record.EventRecorder
+	scheme   *runtime.Scheme
+	log      logr.Logger
+
+	tasks       []Task
+	muTasks     sync.RWMutex
+	rateLimiter ratelimiter.RateLimiter
+}
+
+// NewApolloBackupReconciler creates a new reconciler for ApolloBackup objects.
+func NewApolloBackupReconciler(p ReconcilerParams) *ApolloBackupReconciler {
+	r := &ApolloBackupReconciler{
+		Client:      p.Client,
+		log:         p.Log,
+		scheme:      p.Scheme,
+		tasks:       p.Tasks,
+		rateLimiter: p.RateLimiter,
+		recorder:    p.Recorder,
+	}
+	if len(r.tasks) == 0 {
+		r.tasks = []Task{
+			{
+				backup.Backup,
+				"backup",
+				true,
+			},
+		}
+	}
+	return r
+}
+
+//+kubebuilder:rbac:groups=apolloconfig.com,resources=apollobackups,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apolloconfig.com,resources=apollobackups/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=apolloconfig.com,resources=apollobackups/finalizers,verbs=update
+
+// Reconcile dumps the databases of the target of an ApolloBackup once.
+func (r *ApolloBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.log.WithValues("ApolloBackup", req.NamespacedName)
+	log.Info("ApolloBackupReconciler Reconcile")
+	var instance apolloiov1alpha1.ApolloBackup
+	if err := r.Get(ctx, req.NamespacedName, &instance); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			log.Error(err, "unable to fetch ApolloBackup")
+		}
+
+		// we'll ignore not-found errors, since they can't be fixed by an immediate
+		// requeue (we'll need to wait for a new notification), and we can get them
+		// on deleted requests.
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	params := models.Params{
+		Client:   r.Client,
+		Log:      log,
+		Scheme:   r.scheme,
+		Recorder: r.recorder,
+	}
+
+	if err := r.RunTasks(ctx, &instance, params); err != nil {
+		// NOTE 返回错误时按指数退避重试，成功后只由watch事件和resync触发调谐
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// RunTasks runs all the tasks associated with this reconciler.
+func (r *ApolloBackupReconciler) RunTasks(ctx context.Context, instance client.Object, params models.Params) error {
+	r.muTasks.RLock()
+	defer r.muTasks.RUnlock()
+	for _, task := range r.tasks {
+		if err := task.Do(ctx, instance, params); err != nil {
+			// If we get an error that occurs because a pod is being terminated, then exit this loop
+			if k8serrors.IsForbidden(err) && k8serrors.HasStatusCause(err, corev1.NamespaceTerminatingCause) {
+				r.log.V(2).Info("Exiting reconcile loop because namespace is being terminated", "namespace", instance.GetNamespace())
+				return nil
+			}
+			r.log.Error(err, fmt.Sprintf("failed to reconcile %s", task.Name))
+			if task.BailOnError {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ApolloBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&apolloiov1alpha1.ApolloBackup{}, builder.WithPredicates(primaryPredicate())).
+		Owns(&batchv1.Job{}, builder.WithPredicates(ownedPredicate())).
+		WithOptions(controller.Options{RateLimiter: r.rateLimiter}).
+		Complete(r)
+}
@@ -19,20 +19,30 @@ package controllers
 import (
 	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
 	"apolloconfig.com/apollo-operator/pkg/reconcile"
+	"apolloconfig.com/apollo-operator/pkg/reconcile/apolloenvironment"
 	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"apolloconfig.com/apollo-operator/pkg/utils"
 	"context"
 	"fmt"
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 	"sync"
-	"time"
 )
 
 // ApolloEnvironmentReconciler reconciles a ApolloEnvironment object
@@ -43,21 +53,28 @@ type ApolloEnvironmentReconciler struct {
 	log      logr.Logger
 	//config   config.Config
 
-	tasks   []Task
-	muTasks sync.RWMutex
+	tasks       []Task
+	muTasks     sync.RWMutex
+	rateLimiter ratelimiter.RateLimiter
 }
 
 // NewApolloEnvironmentReconciler creates a new reconciler for ApolloPortal objects.
 func NewApolloEnvironmentReconciler(p ReconcilerParams) *ApolloEnvironmentReconciler {
 	r := &ApolloEnvironmentReconciler{
-		Client:   p.Client,
-		log:      p.Log,
-		scheme:   p.Scheme,
-		tasks:    p.Tasks,
-		recorder: p.Recorder,
+		Client:      p.Client,
+		log:         p.Log,
+		scheme:      p.Scheme,
+		tasks:       p.Tasks,
+		rateLimiter: p.RateLimiter,
+		recorder:    p.Recorder,
 	}
 	if len(r.tasks) == 0 {
 		r.tasks = []Task{
+			{
+				reconcile.Secret,
+				"secrets",
+				true,
+			},
 			{
 				reconcile.ConfigMaps,
 				"configmaps",
@@ -73,21 +90,61 @@ func NewApolloEnvironmentReconciler(p ReconcilerParams) *ApolloEnvironmentReconc
 				"endpoints",
 				true,
 			},
+			{
+				reconcile.NetworkPolicies,
+				"networkpolicies",
+				true,
+			},
 			{
 				reconcile.Services,
 				"services",
 				true,
 			},
+			{
+				reconcile.Job,
+				"jobs",
+				true,
+			},
+			{
+				reconcile.Schema,
+				"schema",
+				true,
+			},
 			{
 				reconcile.Deployments,
 				"deployments",
 				true,
 			},
+			{
+				reconcile.HorizontalPodAutoscalers,
+				"horizontalpodautoscalers",
+				true,
+			},
+			{
+				reconcile.PodDisruptionBudgets,
+				"poddisruptionbudgets",
+				true,
+			},
+			{
+				reconcile.Certificates,
+				"certificates",
+				true,
+			},
 			{
 				reconcile.Ingresses,
 				"ingresses",
 				true,
 			},
+			{
+				reconcile.HTTPRoutes,
+				"httproutes",
+				true,
+			},
+			{
+				reconcile.Routes,
+				"routes",
+				true,
+			},
 			{
 				reconcile.Self,
 				"apolloenvironment",
@@ -135,14 +192,32 @@ func (r *ApolloEnvironmentReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		Scheme:   r.scheme,
 		Recorder: r.recorder,
 	}
-	// TODO Add default values for instance
+	if !instance.DeletionTimestamp.IsZero() {
+		instance.Default()
+		done, err := reconcile.Finalize(ctx, &instance, params, instance.Spec.DeletionPolicy)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !done {
+			return ctrl.Result{RequeueAfter: finalizePollInterval}, nil
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// NOTE Add the finalizer first, the response of the patch overwrites the defaults applied in memory
+	if err := reconcile.AddFinalizer(ctx, &instance, params); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// NOTE Apply the defaults in memory in case the webhook is disabled
+	instance.Default()
 
 	if err := r.RunTasks(ctx, &instance, params); err != nil {
-		//return ctrl.Result{}, err
-		return ctrl.Result{RequeueAfter: time.Second * 5}, err
+		// NOTE 返回错误时按指数退避重试，成功后只由watch事件和resync触发调谐
+		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{RequeueAfter: time.Second * 3}, nil
+	return ctrl.Result{}, nil
 }
 
 // RunTasks runs all the tasks associated with this reconciler.
@@ -166,14 +241,51 @@ func (r *ApolloEnvironmentReconciler) RunTasks(ctx context.Context, instance cli
 	return nil
 }
 
+// environmentSecretRefs returns the index keys of the Secrets referenced by an ApolloEnvironment.
+func environmentSecretRefs(obj client.Object) []string {
+	instance := obj.(*apolloiov1alpha1.ApolloEnvironment)
+	return secretNames(instance.Spec.ConfigDB.UsernameSecretRef, instance.Spec.ConfigDB.PasswordSecretRef)
+}
+
+// environmentsForSecret enqueues the ApolloEnvironments referencing the changed Secret.
+func (r *ApolloEnvironmentReconciler) environmentsForSecret(obj client.Object) []ctrl.Request {
+	instances := &apolloiov1alpha1.ApolloEnvironmentList{}
+	if err := r.List(context.Background(), instances, client.InNamespace(obj.GetNamespace()), client.MatchingFields{secretRefIndex: obj.GetName()}); err != nil {
+		r.log.Error(err, "failed to list the apolloenvironments referencing the secret", "secret", types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}.String())
+		return nil
+	}
+
+	requests := make([]ctrl.Request, 0, len(instances.Items))
+	for _, instance := range instances.Items {
+		requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: instance.Namespace, Name: instance.Name}})
+	}
+	return requests
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *ApolloEnvironmentReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&apolloiov1alpha1.ApolloEnvironment{}).
-		Owns(&corev1.ConfigMap{}).
-		Owns(&corev1.ServiceAccount{}).
-		Owns(&corev1.Service{}).
-		Owns(&appsv1.Deployment{}).
-		Owns(&networkingv1.Ingress{}).
-		Complete(r)
+	reconcile.Register(&apolloiov1alpha1.ApolloEnvironment{}, apolloenvironment.NewApolloEnvironment())
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &apolloiov1alpha1.ApolloEnvironment{}, secretRefIndex, environmentSecretRefs); err != nil {
+		return fmt.Errorf("failed to index the secret references: %w", err)
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&apolloiov1alpha1.ApolloEnvironment{}, builder.WithPredicates(primaryPredicate())).
+		Owns(&corev1.ConfigMap{}, builder.WithPredicates(ownedPredicate())).
+		Owns(&corev1.Secret{}, builder.WithPredicates(ownedPredicate())).
+		Owns(&corev1.ServiceAccount{}, builder.WithPredicates(ownedPredicate())).
+		Owns(&corev1.Service{}, builder.WithPredicates(ownedPredicate())).
+		Owns(&appsv1.Deployment{}, builder.WithPredicates(ownedPredicate())).
+		Owns(&autoscalingv2.HorizontalPodAutoscaler{}, builder.WithPredicates(specPredicate())).
+		Owns(&policyv1.PodDisruptionBudget{}, builder.WithPredicates(specPredicate())).
+		Owns(&batchv1.Job{}, builder.WithPredicates(ownedPredicate())).
+		Owns(&networkingv1.Ingress{}, builder.WithPredicates(ownedPredicate())).
+		Owns(&networkingv1.NetworkPolicy{}, builder.WithPredicates(ownedPredicate())).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.environmentsForSecret)).
+		WithOptions(controller.Options{RateLimiter: r.rateLimiter})
+	b = ownsOptional(b, mgr, utils.CertificateGVK, builder.WithPredicates(ownedPredicate()))
+	b = ownsOptional(b, mgr, utils.HTTPRouteGVK, builder.WithPredicates(specPredicate()))
+	b = ownsOptional(b, mgr, utils.RouteGVK, builder.WithPredicates(specPredicate()))
+	return b.Complete(r)
 }
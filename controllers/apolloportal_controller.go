@@ -19,22 +19,30 @@ package controllers
 import (
 	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
 	"apolloconfig.com/apollo-operator/pkg/reconcile"
+	"apolloconfig.com/apollo-operator/pkg/reconcile/apolloportal"
 	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"apolloconfig.com/apollo-operator/pkg/utils"
 	"context"
 	"fmt"
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
-	"reflect"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"strconv"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 	"sync"
-	"time"
 )
 
 // ApolloPortalReconciler reconciles a ApolloPortal object
@@ -45,8 +53,9 @@ type ApolloPortalReconciler struct {
 	log      logr.Logger
 	//config   config.Config
 
-	tasks   []Task
-	muTasks sync.RWMutex
+	tasks       []Task
+	muTasks     sync.RWMutex
+	rateLimiter ratelimiter.RateLimiter
 }
 
 // NewApolloPortalReconciler creates a new reconciler for ApolloPortal objects.
@@ -56,11 +65,17 @@ func NewApolloPortalReconciler(p ReconcilerParams) *ApolloPortalReconciler {
 		log:    p.Log,
 		scheme: p.Scheme,
 		//config:   p.Config,
-		tasks:    p.Tasks,
-		recorder: p.Recorder,
+		tasks:       p.Tasks,
+		rateLimiter: p.RateLimiter,
+		recorder:    p.Recorder,
 	}
 	if len(r.tasks) == 0 {
 		r.tasks = []Task{
+			{
+				reconcile.Secret,
+				"secrets",
+				true,
+			},
 			{
 				reconcile.ConfigMaps,
 				"configmaps",
@@ -76,21 +91,61 @@ func NewApolloPortalReconciler(p ReconcilerParams) *ApolloPortalReconciler {
 				"endpoints",
 				true,
 			},
+			{
+				reconcile.NetworkPolicies,
+				"networkpolicies",
+				true,
+			},
 			{
 				reconcile.Services,
 				"services",
 				true,
 			},
+			{
+				reconcile.Job,
+				"jobs",
+				true,
+			},
+			{
+				reconcile.Schema,
+				"schema",
+				true,
+			},
 			{
 				reconcile.Deployments,
 				"deployments",
 				true,
 			},
+			{
+				reconcile.HorizontalPodAutoscalers,
+				"horizontalpodautoscalers",
+				true,
+			},
+			{
+				reconcile.PodDisruptionBudgets,
+				"poddisruptionbudgets",
+				true,
+			},
+			{
+				reconcile.Certificates,
+				"certificates",
+				true,
+			},
 			{
 				reconcile.Ingresses,
 				"ingresses",
 				true,
 			},
+			{
+				reconcile.HTTPRoutes,
+				"httproutes",
+				true,
+			},
+			{
+				reconcile.Routes,
+				"routes",
+				true,
+			},
 			{
 				reconcile.Self,
 				"apolloportal",
@@ -104,6 +159,7 @@ func NewApolloPortalReconciler(p ReconcilerParams) *ApolloPortalReconciler {
 //+kubebuilder:rbac:groups=apolloconfig.com,resources=apolloportals,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apolloconfig.com,resources=apolloportals/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=apolloconfig.com,resources=apolloportals/finalizers,verbs=update
+//+kubebuilder:rbac:groups=apolloconfig.com,resources=apolloenvironments,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -137,14 +193,32 @@ func (r *ApolloPortalReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		Scheme:   r.scheme,
 		Recorder: r.recorder,
 	}
-	// TODO 为 instance 增加默认值
+	if !instance.DeletionTimestamp.IsZero() {
+		instance.Default()
+		done, err := reconcile.Finalize(ctx, &instance, params, instance.Spec.DeletionPolicy)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !done {
+			return ctrl.Result{RequeueAfter: finalizePollInterval}, nil
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// NOTE Add the finalizer first, the response of the patch overwrites the defaults applied in memory
+	if err := reconcile.AddFinalizer(ctx, &instance, params); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// NOTE 未启用webhook时，在内存中补齐默认值
+	instance.Default()
 
 	if err := r.RunTasks(ctx, &instance, params); err != nil {
-		//return ctrl.Result{}, err
-		return ctrl.Result{RequeueAfter: time.Second * 5}, err
+		// NOTE 返回错误时按指数退避重试，成功后只由watch事件和resync触发调谐
+		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{RequeueAfter: time.Second * 3}, nil
+	return ctrl.Result{}, nil
 }
 
 // RunTasks runs all the tasks associated with this reconciler.
@@ -168,38 +242,89 @@ func (r *ApolloPortalReconciler) RunTasks(ctx context.Context, instance client.O
 	return nil
 }
 
-func setDefaults(p any) {
-	// Iterate over the fields of the ApolloPortalSpec struct using reflection
-	// and set the default value for each field if the field is not provided
-	// by the caller of the constructor function.
-	for i := 0; i < reflect.TypeOf(p).NumField(); i++ {
-		field := reflect.TypeOf(p).Field(i)
-
-		if value, ok := field.Tag.Lookup("default"); ok {
-			switch field.Type.Kind() {
-			case reflect.String:
-				if reflect.ValueOf(p).Field(i).String() == "" {
-					reflect.ValueOf(p).Field(i).SetString(value)
-				}
-			case reflect.Int:
-				if reflect.ValueOf(p).Field(i).Int() == 0 {
-					if intValue, err := strconv.ParseInt(value, 10, 32); err == nil {
-						reflect.ValueOf(p).Field(i).SetInt(intValue)
-					}
-				}
-			}
+// environmentRefIndex indexes the ApolloPortals by the "namespace/name" of the ApolloEnvironments they reference.
+const environmentRefIndex = ".spec.environments.environmentRef"
+
+// environmentRefs returns the index keys of the ApolloEnvironments referenced by an ApolloPortal.
+func environmentRefs(obj client.Object) []string {
+	instance := obj.(*apolloiov1alpha1.ApolloPortal)
+	refs := make([]string, 0, len(instance.Spec.Environments))
+	for _, env := range instance.Spec.Environments {
+		namespace := env.EnvironmentRef.Namespace
+		if namespace == "" {
+			namespace = instance.Namespace
 		}
+		refs = append(refs, types.NamespacedName{Namespace: namespace, Name: env.EnvironmentRef.Name}.String())
+	}
+	return refs
+}
+
+// portalsForEnvironment enqueues the ApolloPortals referencing the changed ApolloEnvironment.
+func (r *ApolloPortalReconciler) portalsForEnvironment(obj client.Object) []ctrl.Request {
+	portals := &apolloiov1alpha1.ApolloPortalList{}
+	key := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}.String()
+	if err := r.List(context.Background(), portals, client.MatchingFields{environmentRefIndex: key}); err != nil {
+		r.log.Error(err, "failed to list the apolloportals referencing the apolloenvironment", "apolloenvironment", key)
+		return nil
+	}
+
+	requests := make([]ctrl.Request, 0, len(portals.Items))
+	for _, portal := range portals.Items {
+		requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: portal.Namespace, Name: portal.Name}})
 	}
+	return requests
+}
+
+// portalSecretRefs returns the index keys of the Secrets referenced by an ApolloPortal.
+func portalSecretRefs(obj client.Object) []string {
+	instance := obj.(*apolloiov1alpha1.ApolloPortal)
+	return secretNames(instance.Spec.PortalDB.UsernameSecretRef, instance.Spec.PortalDB.PasswordSecretRef)
+}
+
+// portalsForSecret enqueues the ApolloPortals referencing the changed Secret.
+func (r *ApolloPortalReconciler) portalsForSecret(obj client.Object) []ctrl.Request {
+	instances := &apolloiov1alpha1.ApolloPortalList{}
+	if err := r.List(context.Background(), instances, client.InNamespace(obj.GetNamespace()), client.MatchingFields{secretRefIndex: obj.GetName()}); err != nil {
+		r.log.Error(err, "failed to list the apolloportals referencing the secret", "secret", types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}.String())
+		return nil
+	}
+
+	requests := make([]ctrl.Request, 0, len(instances.Items))
+	for _, instance := range instances.Items {
+		requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: instance.Namespace, Name: instance.Name}})
+	}
+	return requests
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ApolloPortalReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&apolloiov1alpha1.ApolloPortal{}).
-		Owns(&corev1.ConfigMap{}).
-		Owns(&corev1.ServiceAccount{}).
-		Owns(&corev1.Service{}).
-		Owns(&appsv1.Deployment{}).
-		Owns(&networkingv1.Ingress{}).
-		Complete(r)
+	reconcile.Register(&apolloiov1alpha1.ApolloPortal{}, apolloportal.NewApolloPortal())
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &apolloiov1alpha1.ApolloPortal{}, secretRefIndex, portalSecretRefs); err != nil {
+		return fmt.Errorf("failed to index the secret references: %w", err)
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &apolloiov1alpha1.ApolloPortal{}, environmentRefIndex, environmentRefs); err != nil {
+		return fmt.Errorf("failed to index the environment references: %w", err)
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&apolloiov1alpha1.ApolloPortal{}, builder.WithPredicates(primaryPredicate())).
+		Owns(&corev1.ConfigMap{}, builder.WithPredicates(ownedPredicate())).
+		Owns(&corev1.Secret{}, builder.WithPredicates(ownedPredicate())).
+		Owns(&corev1.ServiceAccount{}, builder.WithPredicates(ownedPredicate())).
+		Owns(&corev1.Service{}, builder.WithPredicates(ownedPredicate())).
+		Owns(&appsv1.Deployment{}, builder.WithPredicates(ownedPredicate())).
+		Owns(&autoscalingv2.HorizontalPodAutoscaler{}, builder.WithPredicates(specPredicate())).
+		Owns(&policyv1.PodDisruptionBudget{}, builder.WithPredicates(specPredicate())).
+		Owns(&batchv1.Job{}, builder.WithPredicates(ownedPredicate())).
+		Owns(&networkingv1.Ingress{}, builder.WithPredicates(ownedPredicate())).
+		Owns(&networkingv1.NetworkPolicy{}, builder.WithPredicates(ownedPredicate())).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.portalsForSecret)).
+		Watches(&source.Kind{Type: &apolloiov1alpha1.ApolloEnvironment{}}, handler.EnqueueRequestsFromMapFunc(r.portalsForEnvironment)).
+		WithOptions(controller.Options{RateLimiter: r.rateLimiter})
+	b = ownsOptional(b, mgr, utils.CertificateGVK, builder.WithPredicates(ownedPredicate()))
+	b = ownsOptional(b, mgr, utils.HTTPRouteGVK, builder.WithPredicates(specPredicate()))
+	b = ownsOptional(b, mgr, utils.RouteGVK, builder.WithPredicates(specPredicate()))
+	return b.Complete(r)
 }
@@ -0,0 +1,145 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"apolloconfig.com/apollo-operator/pkg/reconcile/backup"
+	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"context"
+	"fmt"
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
+
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+)
+
+// restorePollInterval is how often a running restore checks the backup and the scale down of its target.
+const restorePollInterval = 5 * time.Second
+
+// ApolloRestoreReconciler reconciles a ApolloRestore object
+type ApolloRestoreReconciler struct {
+	client.Client
+	recorder record.EventRecorder
+	scheme   *runtime.Scheme
+	log      logr.Logger
+
+	tasks       []Task
+	muTasks     sync.RWMutex
+	rateLimiter ratelimiter.RateLimiter
+}
+
+// NewApolloRestoreReconciler creates a new reconciler for ApolloRestore objects.
+func NewApolloRestoreReconciler(p ReconcilerParams) *ApolloRestoreReconciler {
+	r := &ApolloRestoreReconciler{
+		Client:      p.Client,
+		log:         p.Log,
+		scheme:      p.Scheme,
+		tasks:       p.Tasks,
+		rateLimiter: p.RateLimiter,
+		recorder:    p.Recorder,
+	}
+	if len(r.tasks) == 0 {
+		r.tasks = []Task{
+			{
+				backup.Restore,
+				"restore",
+				true,
+			},
+		}
+	}
+	return r
+}
+
+//+kubebuilder:rbac:groups=apolloconfig.com,resources=apollorestores,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apolloconfig.com,resources=apollorestores/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=apolloconfig.com,resources=apollorestores/finalizers,verbs=update
+
+// Reconcile restores the ApolloBackup of an ApolloRestore into the databases of its target.
+func (r *ApolloRestoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.log.WithValues("ApolloRestore", req.NamespacedName)
+	log.Info("ApolloRestoreReconciler Reconcile")
+	var instance apolloiov1alpha1.ApolloRestore
+	if err := r.Get(ctx, req.NamespacedName, &instance); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			log.Error(err, "unable to fetch ApolloRestore")
+		}
+
+		// we'll ignore not-found errors, since they can't be fixed by an immediate
+		// requeue (we'll need to wait for a new notification), and we can get them
+		// on deleted requests.
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	params := models.Params{
+		Client:   r.Client,
+		Log:      log,
+		Scheme:   r.scheme,
+		Recorder: r.recorder,
+	}
+
+	if err := r.RunTasks(ctx, &instance, params); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// NOTE 等待备份完成和deployment缩容时没有watch事件，恢复结束前定时检查
+	if instance.Status.Phase != apolloiov1alpha1.BackupPhaseCompleted && instance.Status.Phase != apolloiov1alpha1.BackupPhaseFailed {
+		return ctrl.Result{RequeueAfter: restorePollInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// RunTasks runs all the tasks associated with this reconciler.
+func (r *ApolloRestoreReconciler) RunTasks(ctx context.Context, instance client.Object, params models.Params) error {
+	r.muTasks.RLock()
+	defer r.muTasks.RUnlock()
+	for _, task := range r.tasks {
+		if err := task.Do(ctx, instance, params); err != nil {
+			// If we get an error that occurs because a pod is being terminated, then exit this loop
+			if k8serrors.IsForbidden(err) && k8serrors.HasStatusCause(err, corev1.NamespaceTerminatingCause) {
+				r.log.V(2).Info("Exiting reconcile loop because namespace is being terminated", "namespace", instance.GetNamespace())
+				return nil
+			}
+			r.log.Error(err, fmt.Sprintf("failed to reconcile %s", task.Name))
+			if task.BailOnError {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ApolloRestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&apolloiov1alpha1.ApolloRestore{}, builder.WithPredicates(primaryPredicate())).
+		Owns(&batchv1.Job{}, builder.WithPredicates(ownedPredicate())).
+		WithOptions(controller.Options{RateLimiter: r.rateLimiter}).
+		Complete(r)
+}
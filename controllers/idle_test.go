@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	"apolloconfig.com/apollo-operator/pkg/utils/naming"
+)
+
+// requestCounter counts the API requests of a client, the long running watches are not counted.
+type requestCounter struct {
+	requests int64
+	next     http.RoundTripper
+}
+
+func (c *requestCounter) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Query().Get("watch") != "true" {
+		atomic.AddInt64(&c.requests, 1)
+	}
+	return c.next.RoundTrip(req)
+}
+
+func (c *requestCounter) count() int64 {
+	return atomic.LoadInt64(&c.requests)
+}
+
+var _ = Describe("Idle reconciliation", func() {
+	const (
+		// settleTime lets the reconciles triggered by the creation of the owned objects finish.
+		settleTime = 10 * time.Second
+		// idleTime is the window in which no reconcile should be triggered.
+		idleTime = 30 * time.Second
+	)
+
+	It("makes almost no API calls for an ApolloPortal which did not change", func() {
+		counter := &requestCounter{}
+		config := rest.CopyConfig(cfg)
+		config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			counter.next = rt
+			return counter
+		})
+
+		mgr, err := ctrl.NewManager(config, ctrl.Options{
+			Scheme:             scheme.Scheme,
+			MetricsBindAddress: "0",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(NewApolloPortalReconciler(ReconcilerParams{
+			Client:   mgr.GetClient(),
+			Log:      ctrl.Log.WithName("controllers").WithName("ApolloPortal"),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("apollo-portal-operator"),
+		}).SetupWithManager(mgr)).To(Succeed())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			defer GinkgoRecover()
+			Expect(mgr.Start(ctx)).To(Succeed())
+		}()
+
+		portal := &apolloiov1alpha1.ApolloPortal{
+			ObjectMeta: metav1.ObjectMeta{Name: "idle", Namespace: "default"},
+			Spec: apolloiov1alpha1.ApolloPortalSpec{
+				PortalDB: apolloiov1alpha1.PortalDB{
+					Host:     "10.0.0.1",
+					Port:     3306,
+					Username: "apollo",
+					Password: "apollo",
+					DBName:   "ApolloPortalDB",
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, portal)).To(Succeed())
+		defer func() {
+			Expect(k8sClient.Delete(context.Background(), portal)).To(Succeed())
+		}()
+
+		By("waiting for the portal to be reconciled")
+		Eventually(func() error {
+			deployment := &appsv1.Deployment{}
+			return k8sClient.Get(ctx, types.NamespacedName{Namespace: portal.Namespace, Name: naming.PortalDeployment(portal)}, deployment)
+		}, time.Minute, time.Second).Should(Succeed())
+		time.Sleep(settleTime)
+
+		By("counting the API calls while nothing changes")
+		before := counter.count()
+		time.Sleep(idleTime)
+		// NOTE 之前每3秒重新调谐一次，30秒内会有上百次请求
+		Expect(counter.count() - before).To(BeNumerically("<=", 1))
+	})
+})
@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// primaryPredicate passes the updates of a reconciled instance which change its spec, labels or annotations.
+// The status written by the reconciler itself does not trigger another reconcile.
+func primaryPredicate() predicate.Predicate {
+	return predicate.Or(
+		predicate.GenerationChangedPredicate{},
+		predicate.LabelChangedPredicate{},
+		// NOTE 密码轮换和恢复都是通过注解触发的
+		predicate.AnnotationChangedPredicate{},
+	)
+}
+
+// ownedPredicate passes the updates of an owned object which change its generation, its labels, or its content.
+// The content is compared for the objects without a generation, e.g. configmaps and secrets, and for the status
+// of the workloads. Updates only touching the resourceVersion or the managed fields are dropped.
+func ownedPredicate() predicate.Predicate {
+	return predicate.Or(
+		predicate.GenerationChangedPredicate{},
+		predicate.LabelChangedPredicate{},
+		predicate.Funcs{UpdateFunc: contentChanged},
+	)
+}
+
+// contentChanged reports whether the objects of e differ by more than their resourceVersion and managed fields.
+func contentChanged(e event.UpdateEvent) bool {
+	if e.ObjectOld == nil || e.ObjectNew == nil {
+		return false
+	}
+	oldObj := e.ObjectOld.DeepCopyObject().(client.Object)
+	newObj := e.ObjectNew.DeepCopyObject().(client.Object)
+	for _, obj := range []client.Object{oldObj, newObj} {
+		obj.SetResourceVersion("")
+		obj.SetManagedFields(nil)
+	}
+	return !apiequality.Semantic.DeepEqual(oldObj, newObj)
+}
+
+// specPredicate passes the updates of an owned object which change its generation or its labels.
+// It is used for the objects whose status changes all the time, e.g. the current metrics of a horizontalpodautoscaler.
+func specPredicate() predicate.Predicate {
+	return predicate.Or(
+		predicate.GenerationChangedPredicate{},
+		predicate.LabelChangedPredicate{},
+	)
+}
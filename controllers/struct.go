@@ -4,8 +4,15 @@ import (
 	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
 	"context"
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -17,6 +24,9 @@ type ReconcilerParams struct { // 感觉可以改个名字，不叫 Params
 	Scheme   *runtime.Scheme
 	Log      logr.Logger
 	Tasks    []Task
+
+	// RateLimiter delays the retries of failed reconciles, it defaults to the exponential backoff of controller-runtime.
+	RateLimiter ratelimiter.RateLimiter
 }
 
 // Task represents a reconciliation task to be executed by the reconciler.
@@ -25,3 +35,34 @@ type Task struct {
 	Name        string
 	BailOnError bool
 }
+
+// finalizePollInterval is the interval to check the cleanup of a deleted instance, e.g. its snapshot,
+// which is done by resources the instance does not watch.
+const finalizePollInterval = 5 * time.Second
+
+// ownsOptional watches the objects of gvk owned by the reconciled kind as unstructured objects when their API is installed,
+// e.g. the certificates of cert-manager. The operator runs without the API, it has to be restarted after the API is installed.
+func ownsOptional(b *builder.Builder, mgr ctrl.Manager, gvk schema.GroupVersionKind, opts ...builder.OwnsOption) *builder.Builder {
+	if _, err := mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+		mgr.GetLogger().Info("the API is not installed, its objects are not watched", "gvk", gvk.String())
+		return b
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	return b.Owns(obj, opts...)
+}
+
+// secretRefIndex indexes the instances by the names of the Secrets they reference in their namespace,
+// e.g. the database credentials, so that the instances are reconciled again when the Secrets change.
+const secretRefIndex = ".spec.secretRefs"
+
+// secretNames returns the index keys of the Secrets selected by refs, the nil refs are skipped.
+func secretNames(refs ...*corev1.SecretKeySelector) []string {
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if ref != nil && ref.Name != "" {
+			names = append(names, ref.Name)
+		}
+	}
+	return names
+}
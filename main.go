@@ -19,6 +19,7 @@ package main
 import (
 	"flag"
 	"os"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -27,9 +28,11 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
 
 	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
 	"apolloconfig.com/apollo-operator/controllers"
@@ -52,11 +55,18 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var syncPeriod time.Duration
+	var backoffBaseDelay time.Duration
+	var backoffMaxDelay time.Duration
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&syncPeriod, "sync-period", 10*time.Hour,
+		"The period after which every resource is reconciled again even without any change, reconciles are otherwise only triggered by watch events.")
+	flag.DurationVar(&backoffBaseDelay, "backoff-base-delay", time.Second, "The delay before the first retry of a failed reconcile, it doubles on every failure.")
+	flag.DurationVar(&backoffMaxDelay, "backoff-max-delay", 5*time.Minute, "The maximum delay between the retries of a failed reconcile.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -72,6 +82,7 @@ func main() {
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "f54cb3e5.apolloconfig.com",
+		SyncPeriod:             &syncPeriod,
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -89,35 +100,87 @@ func main() {
 		os.Exit(1)
 	}
 
+	// NOTE 调谐失败时按指数退避重试，每个controller单独记录失败次数
+	newRateLimiter := func() ratelimiter.RateLimiter {
+		return workqueue.NewItemExponentialFailureRateLimiter(backoffBaseDelay, backoffMaxDelay)
+	}
+
 	// clientset, _ := kubernetes.NewForConfig(ctrl.GetConfigOrDie())
 	if err = controllers.NewApolloEnvironmentReconciler(controllers.ReconcilerParams{
-		Client:   mgr.GetClient(),
-		Log:      ctrl.Log.WithName("controllers").WithName("ApolloEnvironment"),
-		Scheme:   mgr.GetScheme(),
-		Recorder: mgr.GetEventRecorderFor("apollo-environment-operator"),
+		Client:      mgr.GetClient(),
+		Log:         ctrl.Log.WithName("controllers").WithName("ApolloEnvironment"),
+		Scheme:      mgr.GetScheme(),
+		RateLimiter: newRateLimiter(),
+		Recorder:    mgr.GetEventRecorderFor("apollo-environment-operator"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ApolloEnvironment")
 		os.Exit(1)
 	}
 	if err = controllers.NewApolloPortalReconciler(controllers.ReconcilerParams{
-		Client:   mgr.GetClient(),
-		Log:      ctrl.Log.WithName("controllers").WithName("ApolloPortal"),
-		Scheme:   mgr.GetScheme(),
-		Recorder: mgr.GetEventRecorderFor("apollo-portal-operator"),
+		Client:      mgr.GetClient(),
+		Log:         ctrl.Log.WithName("controllers").WithName("ApolloPortal"),
+		Scheme:      mgr.GetScheme(),
+		RateLimiter: newRateLimiter(),
+		Recorder:    mgr.GetEventRecorderFor("apollo-portal-operator"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ApolloPortal")
 		os.Exit(1)
 	}
 
 	if err = controllers.NewApolloAllInOneReconciler(controllers.ReconcilerParams{
-		Client:   mgr.GetClient(),
-		Log:      ctrl.Log.WithName("controllers").WithName("ApolloAllInOne"),
-		Scheme:   mgr.GetScheme(),
-		Recorder: mgr.GetEventRecorderFor("apollo-allinone-operator"),
+		Client:      mgr.GetClient(),
+		Log:         ctrl.Log.WithName("controllers").WithName("ApolloAllInOne"),
+		Scheme:      mgr.GetScheme(),
+		RateLimiter: newRateLimiter(),
+		Recorder:    mgr.GetEventRecorderFor("apollo-allinone-operator"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ApolloAllInOne")
 		os.Exit(1)
 	}
+	if err = controllers.NewApolloBackupReconciler(controllers.ReconcilerParams{
+		Client:      mgr.GetClient(),
+		Log:         ctrl.Log.WithName("controllers").WithName("ApolloBackup"),
+		Scheme:      mgr.GetScheme(),
+		RateLimiter: newRateLimiter(),
+		Recorder:    mgr.GetEventRecorderFor("apollo-backup-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ApolloBackup")
+		os.Exit(1)
+	}
+	if err = controllers.NewApolloBackupScheduleReconciler(controllers.ReconcilerParams{
+		Client:      mgr.GetClient(),
+		Log:         ctrl.Log.WithName("controllers").WithName("ApolloBackupSchedule"),
+		Scheme:      mgr.GetScheme(),
+		RateLimiter: newRateLimiter(),
+		Recorder:    mgr.GetEventRecorderFor("apollo-backup-schedule-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ApolloBackupSchedule")
+		os.Exit(1)
+	}
+	if err = controllers.NewApolloRestoreReconciler(controllers.ReconcilerParams{
+		Client:      mgr.GetClient(),
+		Log:         ctrl.Log.WithName("controllers").WithName("ApolloRestore"),
+		Scheme:      mgr.GetScheme(),
+		RateLimiter: newRateLimiter(),
+		Recorder:    mgr.GetEventRecorderFor("apollo-restore-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ApolloRestore")
+		os.Exit(1)
+	}
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = (&apolloiov1alpha1.ApolloEnvironment{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ApolloEnvironment")
+			os.Exit(1)
+		}
+		if err = (&apolloiov1alpha1.ApolloPortal{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ApolloPortal")
+			os.Exit(1)
+		}
+		if err = (&apolloiov1alpha1.Apollo{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Apollo")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
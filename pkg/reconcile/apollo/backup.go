@@ -0,0 +1,25 @@
+package apollo
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	"apolloconfig.com/apollo-operator/pkg/utils"
+	"apolloconfig.com/apollo-operator/pkg/utils/naming"
+	"fmt"
+)
+
+// Databases returns the ApolloConfigDB and the ApolloPortalDB of the allinone instance, as dumped by an ApolloBackup.
+func Databases(instance *apolloiov1alpha1.Apollo) []utils.Database {
+	host := fmt.Sprintf("%s.%s", naming.AllInOneDBService(instance), instance.Namespace) // NOTE 一定要确保和apollodbService服务名及命名空间一致
+	port := instance.Spec.Database.Type.DefaultPort()
+	username := utils.SecretKeyRef(nil, naming.AllInOneDBSecret(instance), utils.SecretUsernameKey)
+	password := utils.SecretKeyRef(nil, naming.AllInOneDBSecret(instance), utils.SecretPasswordKey)
+	if databaseMode(instance) == apolloiov1alpha1.DatabaseModeExternal {
+		external := instance.Spec.Database.External
+		host, port, username, password = external.Host, external.Port, external.UsernameSecretRef, external.PasswordSecretRef
+	}
+
+	return []utils.Database{
+		{Host: host, Port: port, Name: configDBName(instance), Username: username, Password: password},
+		{Host: host, Port: port, Name: portalDBName(instance), Username: username, Password: password},
+	}
+}
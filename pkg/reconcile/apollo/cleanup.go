@@ -0,0 +1,36 @@
+package apollo
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"apolloconfig.com/apollo-operator/pkg/utils/naming"
+	"context"
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=delete
+
+// Cleanup deletes the PVC of the database, which is created by the statefulset and not owned by the Apollo,
+// unless the deletion policy retains it. A retained PVC is used again by an Apollo created with the same name.
+func (o ApolloAllInOne) Cleanup(ctx context.Context, instance client.Object, params models.Params, policy apolloiov1alpha1.DeletionPolicy) (bool, error) {
+	if policy == apolloiov1alpha1.DeletionPolicyRetain {
+		return true, nil
+	}
+
+	// NOTE statefulset创建的pvc的名字为 <template>-<statefulset>-<ordinal>，数据库只有一个副本
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s-0", naming.AllInOnePVC(instance), naming.AllInOneStatefulSet(instance)),
+			Namespace: instance.GetNamespace(),
+		},
+	}
+	if err := params.Client.Delete(ctx, pvc); err != nil && !k8serrors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to delete pvc %s: %w", pvc.Name, err)
+	}
+	params.Log.V(2).Info("deleted", "pvc.name", pvc.Name, "pvc.namespace", pvc.Namespace)
+	return true, nil
+}
@@ -8,12 +8,18 @@ import (
 	"context"
 	"fmt"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
-	"k8s.io/apimachinery/pkg/api/resource"
+	policyv1 "k8s.io/api/policy/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strconv"
 	"strings"
 )
 
@@ -21,7 +27,7 @@ import (
 func (o ApolloAllInOne) DesiredConfigMaps(ctx context.Context, instance client.Object, params models.Params) []corev1.ConfigMap {
 	desired := []corev1.ConfigMap{}
 	type builder func(context.Context, client.Object, models.Params) *corev1.ConfigMap
-	for _, builder := range []builder{adminServiceConfig, configServiceConfig, portalServiceConfig} {
+	for _, builder := range []builder{adminServiceConfig, configServiceConfig, portalServiceConfig, apollodbConfig} {
 		cm := builder(ctx, instance, params)
 		// add only the non-nil to the list
 		if cm != nil {
@@ -31,6 +37,36 @@ func (o ApolloAllInOne) DesiredConfigMaps(ctx context.Context, instance client.O
 	return desired
 }
 
+// apollodbConfig holds the extra my.cnf of the allinone mysql database.
+func apollodbConfig(_ context.Context, obj client.Object, params models.Params) *corev1.ConfigMap {
+	instance := obj.(*apolloiov1alpha1.Apollo)
+	if !hasDatabaseConfig(instance) {
+		return nil
+	}
+
+	// NOTE 一定要和volume中使用的名字一致
+	name := naming.AllInOneDBConfigMap(instance)
+	labels := utils.Labels(instance, name, []string{})
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.GetNamespace(),
+			Labels:    labels,
+		},
+		Data: map[string]string{
+			databaseConfigFile: instance.Spec.Database.Config,
+		},
+	}
+}
+
+// hasDatabaseConfig reports whether the allinone database runs with an extra my.cnf.
+func hasDatabaseConfig(instance *apolloiov1alpha1.Apollo) bool {
+	return instance.Spec.Database.Config != "" &&
+		instance.Spec.Database.Type != apolloiov1alpha1.DatabaseTypePostgreSQL &&
+		databaseMode(instance) != apolloiov1alpha1.DatabaseModeExternal
+}
+
 func configServiceConfig(_ context.Context, obj client.Object, params models.Params) *corev1.ConfigMap {
 	instance := obj.(*apolloiov1alpha1.Apollo)
 
@@ -42,17 +78,9 @@ func configServiceConfig(_ context.Context, obj client.Object, params models.Par
 	data := map[string]string{}
 
 	// application-github.properties
-	// TODO 多种数据库支持
-	apolloGithubConfig := []string{
-		fmt.Sprintf("spring.datasource.username = %s", "root"),
-		fmt.Sprintf("spring.datasource.password = %s", "123456"),
-		fmt.Sprintf("spring.datasource.url = jdbc:mysql://%s.%s:%d/%s?%s",
-			naming.AllInOneDBService(instance), // NOTE 一定要确保和apollodbService服务名一致
-			instance.Namespace,                 // NOTE 一定要确保和apollodbService服务的命名空间一致
-			3306,
-			"ApolloConfigDB",
-			"characterEncoding=utf8"),
-
+	// NOTE 用户名和密码通过环境变量从operator生成的secret中注入，不写入configmap
+	apolloGithubConfig := datasourceProperties(instance, configDBName(instance))
+	apolloGithubConfig = append(apolloGithubConfig,
 		// TODO 这里先默认k8s提供的服务发现地址
 		fmt.Sprintf("apollo.config-service.url = http://%s.%s:%d%s",
 			naming.ConfigService(instance), // NOTE 一定要确保和configService服务名一致
@@ -64,7 +92,7 @@ func configServiceConfig(_ context.Context, obj client.Object, params models.Par
 			instance.Namespace,            // NOTE 一定要确保和configService服务的命名空间一致
 			8090,                          // instance.Spec.AdminService.Service.Port
 			""),                           // instance.Spec.AdminService.Config.ContextPath
-	}
+	)
 
 	data["application-github.properties"] = strings.Join(apolloGithubConfig, "\n")
 
@@ -91,17 +119,8 @@ func adminServiceConfig(_ context.Context, obj client.Object, params models.Para
 	data := map[string]string{}
 
 	// application-github.properties
-	// TODO 多种数据库支持
-	apolloGithubConfig := []string{
-		fmt.Sprintf("spring.datasource.username = %s", "root"),
-		fmt.Sprintf("spring.datasource.password = %s", "123456"),
-		fmt.Sprintf("spring.datasource.url = jdbc:mysql://%s.%s:%d/%s?%s",
-			naming.AllInOneDBService(instance), // NOTE 一定要确保和apollodbService服务名一致
-			instance.Namespace,                 // NOTE 一定要确保和apollodbService服务的命名空间一致
-			3306,
-			"ApolloConfigDB",
-			"characterEncoding=utf8"),
-	}
+	// NOTE 用户名和密码通过环境变量从operator生成的secret中注入，不写入configmap
+	apolloGithubConfig := datasourceProperties(instance, configDBName(instance))
 
 	data["application-github.properties"] = strings.Join(apolloGithubConfig, "\n")
 
@@ -143,17 +162,8 @@ func portalServiceConfig(_ context.Context, obj client.Object, params models.Par
 	data["apollo-env.properties"] = strings.Join(apolloEnvConfig, "\n")
 
 	// application-github.properties
-	// TODO 多种数据库支持
-	apolloGithubConfig := []string{
-		fmt.Sprintf("spring.datasource.username = %s", "root"),
-		fmt.Sprintf("spring.datasource.password = %s", "123456"),
-		fmt.Sprintf("spring.datasource.url = jdbc:mysql://%s.%s:%d/%s?%s",
-			naming.AllInOneDBService(instance), // NOTE 一定要确保和apollodbService服务名一致
-			instance.Namespace,                 // NOTE 一定要确保和apollodbService服务的命名空间一致
-			3306,
-			"ApolloPortalDB",
-			"characterEncoding=utf8"),
-	}
+	// NOTE 用户名和密码通过环境变量从operator生成的secret中注入，不写入configmap
+	apolloGithubConfig := datasourceProperties(instance, portalDBName(instance))
 	//if instance.Spec.PortalService.Config.Envs != "" {
 	//	apolloGithubConfig = append(apolloGithubConfig, fmt.Sprintf("apollo.portal.envs = %s", instance.Spec.PortalService.Config.Envs))
 	//}
@@ -176,6 +186,160 @@ func portalServiceConfig(_ context.Context, obj client.Object, params models.Par
 	}
 }
 
+// DesiredSecrets 构建secret对象
+func (o ApolloAllInOne) DesiredSecrets(ctx context.Context, instance client.Object, params models.Params) []corev1.Secret {
+	desired := []corev1.Secret{}
+	type builder func(context.Context, client.Object, models.Params) *corev1.Secret
+	for _, builder := range []builder{apollodbSecret} {
+		secret := builder(ctx, instance, params)
+		// add only the non-nil to the list
+		if secret != nil {
+			desired = append(desired, *secret)
+		}
+	}
+	return desired
+}
+
+// apollodbSecret holds the root password of the allinone database.
+// A new password is generated on every call, the secret reconciler keeps the existing one unless a rotation is requested.
+func apollodbSecret(_ context.Context, obj client.Object, params models.Params) *corev1.Secret {
+	instance := obj.(*apolloiov1alpha1.Apollo)
+	if databaseMode(instance) == apolloiov1alpha1.DatabaseModeExternal {
+		return nil
+	}
+	name := naming.AllInOneDBSecret(instance)
+	labels := utils.Labels(instance, name, []string{})
+
+	data := map[string][]byte{
+		utils.SecretUsernameKey: []byte("root"),
+	}
+	password, err := utils.RandomPassword(24)
+	if err != nil {
+		// NOTE 不返回nil，否则secret会被删除；缺少密码时会保留已有的密码
+		params.Log.Error(err, "failed to generate the database password", "secret.name", name)
+	} else {
+		data[utils.SecretPasswordKey] = []byte(password)
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.GetNamespace(),
+			Labels:    labels,
+			Annotations: map[string]string{
+				utils.DBPasswordRotationAnnotation: rotationToken(instance),
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+}
+
+// rotationToken returns the value of the rotation annotation of the instance, an empty string if never rotated.
+func rotationToken(instance client.Object) string {
+	return instance.GetAnnotations()[utils.DBPasswordRotationAnnotation]
+}
+
+// datasourceEnv injects the credentials of the allinone database from the operator managed secret,
+// or from the secrets of the external database.
+func datasourceEnv(instance *apolloiov1alpha1.Apollo) []corev1.EnvVar {
+	if databaseMode(instance) == apolloiov1alpha1.DatabaseModeExternal {
+		return utils.DatasourceEnv(instance.Spec.Database.External.UsernameSecretRef, instance.Spec.Database.External.PasswordSecretRef)
+	}
+	return utils.DatasourceEnv(
+		utils.SecretKeyRef(nil, naming.AllInOneDBSecret(instance), utils.SecretUsernameKey),
+		utils.SecretKeyRef(nil, naming.AllInOneDBSecret(instance), utils.SecretPasswordKey),
+	)
+}
+
+// databaseMode returns where the allinone database runs, the statefulset by default.
+func databaseMode(instance *apolloiov1alpha1.Apollo) apolloiov1alpha1.DatabaseMode {
+	if instance.Spec.Database.Mode == "" {
+		return apolloiov1alpha1.DatabaseModeMySQL
+	}
+	return instance.Spec.Database.Mode
+}
+
+// configDBName returns the name of the ApolloConfigDB in the allinone database.
+func configDBName(instance *apolloiov1alpha1.Apollo) string {
+	if databaseMode(instance) == apolloiov1alpha1.DatabaseModeExternal {
+		return instance.Spec.Database.External.ConfigDBName
+	}
+	return "ApolloConfigDB" // NOTE 和sql脚本中创建的数据库名保持一致
+}
+
+// portalDBName returns the name of the ApolloPortalDB in the allinone database.
+func portalDBName(instance *apolloiov1alpha1.Apollo) string {
+	if databaseMode(instance) == apolloiov1alpha1.DatabaseModeExternal {
+		return instance.Spec.Database.External.PortalDBName
+	}
+	return "ApolloPortalDB" // NOTE 和sql脚本中创建的数据库名保持一致
+}
+
+// datasourceProperties connects apollo to dbName in the allinone database.
+func datasourceProperties(instance *apolloiov1alpha1.Apollo, dbName string) []string {
+	if databaseMode(instance) == apolloiov1alpha1.DatabaseModeExternal {
+		external := instance.Spec.Database.External
+		return utils.DatasourceProperties(instance.Spec.Database.Type, external.Host, external.Port, dbName, external.ConnectionStringProperties)
+	}
+
+	properties := "characterEncoding=utf8"
+	if instance.Spec.Database.Type == apolloiov1alpha1.DatabaseTypePostgreSQL {
+		properties = ""
+	}
+	return utils.DatasourceProperties(instance.Spec.Database.Type,
+		fmt.Sprintf("%s.%s", naming.AllInOneDBService(instance), instance.Namespace), // NOTE 一定要确保和apollodbService服务名及命名空间一致
+		instance.Spec.Database.Type.DefaultPort(),
+		dbName,
+		properties)
+}
+
+// rootPasswordEnv injects the root credentials of the allinone database into the database containers.
+func rootPasswordEnv(instance *apolloiov1alpha1.Apollo) []corev1.EnvVar {
+	password := &corev1.EnvVarSource{
+		SecretKeyRef: utils.SecretKeyRef(nil, naming.AllInOneDBSecret(instance), utils.SecretPasswordKey),
+	}
+	if instance.Spec.Database.Type == apolloiov1alpha1.DatabaseTypePostgreSQL {
+		return []corev1.EnvVar{
+			{
+				Name: "POSTGRES_USER",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: utils.SecretKeyRef(nil, naming.AllInOneDBSecret(instance), utils.SecretUsernameKey),
+				},
+			},
+			{Name: "POSTGRES_PASSWORD", ValueFrom: password},
+			// NOTE pvc的根目录包含lost+found，initdb要求数据目录为空
+			{Name: "PGDATA", Value: "/var/lib/postgresql/data/pgdata"},
+		}
+	}
+	return []corev1.EnvVar{{Name: "MYSQL_ROOT_PASSWORD", ValueFrom: password}}
+}
+
+// databaseSecret reads the secret of the allinone database, it is nil until the secret has been created.
+func databaseSecret(ctx context.Context, instance *apolloiov1alpha1.Apollo, params models.Params) *corev1.Secret {
+	secret := &corev1.Secret{}
+	nns := types.NamespacedName{Namespace: instance.Namespace, Name: naming.AllInOneDBSecret(instance)}
+	if err := params.Client.Get(ctx, nns, secret); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			params.Log.Error(err, "failed to get the database secret", "secret.name", nns.Name)
+		}
+		return nil
+	}
+	return secret
+}
+
+// setRotatedAnnotation rolls the apollo pods once a rotated database password is in use, not when the rotation is requested.
+func setRotatedAnnotation(ctx context.Context, instance *apolloiov1alpha1.Apollo, params models.Params, template *corev1.PodTemplateSpec) {
+	secret := databaseSecret(ctx, instance, params)
+	if secret == nil || secret.Annotations[utils.DBPasswordRotatedAnnotation] == "" {
+		return
+	}
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[utils.DBPasswordRotatedAnnotation] = secret.Annotations[utils.DBPasswordRotatedAnnotation]
+}
+
 // DesiredEndpoints 构建endpoints对象
 func (o ApolloAllInOne) DesiredEndpoints(ctx context.Context, instance client.Object, params models.Params) []corev1.Endpoints {
 	return []corev1.Endpoints{}
@@ -197,6 +361,9 @@ func (o ApolloAllInOne) DesiredServices(ctx context.Context, instance client.Obj
 
 func apollodbService(ctx context.Context, obj client.Object, params models.Params) *corev1.Service {
 	instance := obj.(*apolloiov1alpha1.Apollo)
+	if databaseMode(instance) == apolloiov1alpha1.DatabaseModeExternal {
+		return nil
+	}
 	name := naming.AllInOneDBService(instance)
 	labels := utils.Labels(instance, name, []string{})
 
@@ -211,8 +378,8 @@ func apollodbService(ctx context.Context, obj client.Object, params models.Param
 			Ports: []corev1.ServicePort{
 				{
 					Protocol:   corev1.ProtocolTCP,
-					Port:       3306,                                               // TODO 稍后修改
-					TargetPort: intstr.IntOrString{Type: intstr.Int, IntVal: 3306}, // TODO 稍后修改
+					Port:       instance.Spec.Database.Type.DefaultPort(),
+					TargetPort: intstr.IntOrString{Type: intstr.Int, IntVal: instance.Spec.Database.Type.DefaultPort()},
 				},
 			},
 			Selector: utils.SelectorLabelsWithCustom(instance, map[string]string{"app": "apollo-db"}),
@@ -322,10 +489,16 @@ func (o ApolloAllInOne) DesiredStatefulSets(ctx context.Context, instance client
 }
 
 func apolloStatefulSet(ctx context.Context, instance client.Object, params models.Params) *appsv1.StatefulSet {
+	if databaseMode(instance.(*apolloiov1alpha1.Apollo)) != apolloiov1alpha1.DatabaseModeMySQL {
+		return nil
+	}
 	name := naming.AllInOneStatefulSet(instance)
 	labels := utils.Labels(instance, name, []string{})
 
 	spec, _ := buildApolloStatefulSetSpec(ctx, instance)
+	if hasDatabaseConfig(instance.(*apolloiov1alpha1.Apollo)) {
+		setConfigHash(ctx, instance, params, &spec.Template)
+	}
 
 	apollodb := &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -341,28 +514,9 @@ func apolloStatefulSet(ctx context.Context, instance client.Object, params model
 func buildApolloStatefulSetSpec(ctx context.Context, obj client.Object) (appsv1.StatefulSetSpec, error) {
 	instance := obj.(*apolloiov1alpha1.Apollo)
 
-	container, _ := buildMysqlContainer(ctx, instance)
-	//initContainer, _ := buildMysqlInitContainer(ctx, instance)
+	template := buildDatabasePodTemplate(ctx, instance)
 	pvc, _ := buildMysqlPVC(ctx, instance)
 
-	template := corev1.PodTemplateSpec{
-		ObjectMeta: metav1.ObjectMeta{
-			Labels: utils.SelectorLabelsWithCustom(instance, map[string]string{"app": "apollo-db"}),
-		},
-		Spec: corev1.PodSpec{
-			Containers: []corev1.Container{container},
-			Volumes: []corev1.Volume{
-				{
-					Name: naming.AllInOneSqlScript(instance),
-					VolumeSource: corev1.VolumeSource{
-						ConfigMap: &corev1.ConfigMapVolumeSource{
-							LocalObjectReference: corev1.LocalObjectReference{Name: naming.AllInOneSqlScriptConfigmap(instance)}, // NOTE 和包含sql语句的configmap的名字保持一致
-						},
-					},
-				},
-			},
-		},
-	}
 	var replicas int32 = 1 // TODO 如果不是1的话，需要使用 headless service
 	return appsv1.StatefulSetSpec{
 		Replicas:        &replicas, // TODO 修改
@@ -371,10 +525,58 @@ func buildApolloStatefulSetSpec(ctx context.Context, obj client.Object) (appsv1.
 		Selector:        &metav1.LabelSelector{MatchLabels: utils.SelectorLabelsWithCustom(instance, map[string]string{"app": "apollo-db"})},
 		Template:        template,
 
-		VolumeClaimTemplates: []corev1.PersistentVolumeClaim{pvc},
+		VolumeClaimTemplates:                 []corev1.PersistentVolumeClaim{pvc},
+		PersistentVolumeClaimRetentionPolicy: instance.Spec.Database.PersistentVolumeClaimRetentionPolicy,
 	}, nil
 }
 
+// buildDatabasePodTemplate builds the pod of the allinone database, the data volume is provided by the statefulset or the embedded deployment.
+func buildDatabasePodTemplate(ctx context.Context, instance *apolloiov1alpha1.Apollo) corev1.PodTemplateSpec {
+	container, _ := buildMysqlContainer(ctx, instance)
+	scriptConfigmap := naming.AllInOneSqlScriptConfigmap(instance)
+	if instance.Spec.Database.Type == apolloiov1alpha1.DatabaseTypePostgreSQL {
+		container, _ = buildPostgreSQLContainer(ctx, instance)
+		scriptConfigmap = naming.AllInOnePostgreSQLScriptConfigmap(instance)
+	}
+	//initContainer, _ := buildMysqlInitContainer(ctx, instance)
+
+	volumes := []corev1.Volume{
+		{
+			Name: naming.AllInOneSqlScript(instance),
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: scriptConfigmap}, // NOTE 和包含sql语句的configmap的名字保持一致
+				},
+			},
+		},
+	}
+	if hasDatabaseConfig(instance) {
+		volumes = append(volumes, corev1.Volume{
+			Name: naming.AllInOneDBConfigMap(instance),
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: naming.AllInOneDBConfigMap(instance)},
+				},
+			},
+		})
+	}
+
+	template := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: utils.SelectorLabelsWithCustom(instance, map[string]string{"app": "apollo-db"}),
+		},
+		Spec: corev1.PodSpec{
+			Containers:   []corev1.Container{container},
+			Volumes:      volumes,
+			NodeSelector: instance.Spec.Database.NodeSelector,
+			Tolerations:  instance.Spec.Database.Tolerations,
+		},
+	}
+	utils.ApplyPodTemplateOverrides(&template, instance.Spec.Database.PodTemplate, utils.DatabaseUserID)
+	utils.ApplyServiceAccount(&template, naming.DBServiceAccount(instance), instance.Spec.Database.ServiceAccount)
+	return template
+}
+
 func buildMysqlContainer(ctx context.Context, instance *apolloiov1alpha1.Apollo) (corev1.Container, error) {
 
 	volumeMounts := []corev1.VolumeMount{
@@ -387,22 +589,25 @@ func buildMysqlContainer(ctx context.Context, instance *apolloiov1alpha1.Apollo)
 			MountPath: "/mnt/sql-script",
 		},
 	}
+	if hasDatabaseConfig(instance) {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      naming.AllInOneDBConfigMap(instance),
+			MountPath: "/etc/mysql/conf.d/" + databaseConfigFile,
+			SubPath:   databaseConfigFile,
+		})
+	}
 
 	container := corev1.Container{
 		Name:  naming.Container(),
-		Image: "mysql:5.7", // TODO 修改
+		Image: instance.Spec.Database.Image,
 		Ports: []corev1.ContainerPort{
 			corev1.ContainerPort{
 				Name:          "mysql-port",
-				ContainerPort: 3306, // TODO 修改
-			},
-		},
-		Env: []corev1.EnvVar{
-			{
-				Name:  "MYSQL_ROOT_PASSWORD", // TODO 修改
-				Value: "123456",              // TODO 修改
+				ContainerPort: instance.Spec.Database.Type.DefaultPort(), // NOTE 和service的targetPort保持一致
+				Protocol:      corev1.ProtocolTCP,
 			},
 		},
+		Env: rootPasswordEnv(instance),
 		Lifecycle: &corev1.Lifecycle{
 			PostStart: &corev1.LifecycleHandler{
 				Exec: &corev1.ExecAction{ // TODO 修改
@@ -413,6 +618,38 @@ func buildMysqlContainer(ctx context.Context, instance *apolloiov1alpha1.Apollo)
 				},
 			},
 		},
+		Resources:    instance.Spec.Database.Resources,
+		VolumeMounts: volumeMounts,
+	}
+	return container, nil
+}
+
+// buildPostgreSQLContainer runs the scripts of the sql configmap through the entrypoint of the postgres image, only on the first start of an empty database.
+func buildPostgreSQLContainer(ctx context.Context, instance *apolloiov1alpha1.Apollo) (corev1.Container, error) {
+
+	volumeMounts := []corev1.VolumeMount{
+		corev1.VolumeMount{
+			Name:      naming.AllInOnePVC(instance), // NOTE 和 volumeClaimTemplates 中内容保持一致
+			MountPath: "/var/lib/postgresql/data",
+		},
+		corev1.VolumeMount{
+			Name:      naming.AllInOneSqlScript(instance),
+			MountPath: "/docker-entrypoint-initdb.d",
+		},
+	}
+
+	container := corev1.Container{
+		Name:  naming.Container(),
+		Image: instance.Spec.Database.Image,
+		Ports: []corev1.ContainerPort{
+			corev1.ContainerPort{
+				Name:          "postgresql-port",
+				ContainerPort: instance.Spec.Database.Type.DefaultPort(), // NOTE 和service的targetPort保持一致
+				Protocol:      corev1.ProtocolTCP,
+			},
+		},
+		Env:          rootPasswordEnv(instance),
+		Resources:    instance.Spec.Database.Resources,
 		VolumeMounts: volumeMounts,
 	}
 	return container, nil
@@ -429,14 +666,9 @@ func buildMysqlInitContainer(ctx context.Context, instance *apolloiov1alpha1.Apo
 	}
 
 	container := corev1.Container{
-		Name:  naming.InitContainer(),
-		Image: "mysql:5.7", // TODO 修改
-		Env: []corev1.EnvVar{
-			{
-				Name:  "MYSQL_ROOT_PASSWORD", // TODO 修改
-				Value: "123456",              // TODO 修改
-			},
-		},
+		Name:         naming.InitContainer(),
+		Image:        instance.Spec.Database.Image,
+		Env:          rootPasswordEnv(instance),
 		Command:      []string{"bash", "-c", "set -ex\n# Copy the SQL script from the ConfigMap to a temporary location.\ncp /mnt/sql-script/initdb.sql /tmp/initdb.sql\n# Wait for the MySQL server to be ready.\nuntil mysql -h mysql-0.mysql -uroot -p${MYSQL_ROOT_PASSWORD} -e \"SELECT 1\"; do sleep 1; done\n# Run the SQL script on the master node.\nif [[ `hostname` =~ -0$ ]]; then\n  mysql -h mysql-0.mysql -uroot -p${MYSQL_ROOT_PASSWORD} \u003c /tmp/initdb.sql\nfi\n"},
 		VolumeMounts: volumeMounts,
 	}
@@ -446,7 +678,6 @@ func buildMysqlInitContainer(ctx context.Context, instance *apolloiov1alpha1.Apo
 func buildMysqlPVC(ctx context.Context, instance *apolloiov1alpha1.Apollo) (corev1.PersistentVolumeClaim, error) {
 	name := naming.AllInOnePVC(instance) // NOTE 和 Container 中内容保持一致
 	//labels := utils.Labels(instance, name, []string{})
-	storageClassName := "standard"
 	return corev1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
@@ -457,21 +688,200 @@ func buildMysqlPVC(ctx context.Context, instance *apolloiov1alpha1.Apollo) (core
 			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}, // TODO 修改
 			Resources: corev1.ResourceRequirements{
 				Requests: corev1.ResourceList{
-					corev1.ResourceStorage: resource.MustParse("2Gi"), // pv大小
+					corev1.ResourceStorage: instance.Spec.Database.Storage.Size, // pv大小
 				},
-			}, // TODO 增加资源限制
-			// TODO 指定存储类 不填使用默认存储类
-			StorageClassName: &storageClassName,
+			},
+			// NOTE 不填使用默认存储类
+			StorageClassName: instance.Spec.Database.Storage.StorageClassName,
 		},
 	}, nil
 }
 
+// DesiredJobs 构建job对象
+func (o ApolloAllInOne) DesiredJobs(ctx context.Context, instance client.Object, params models.Params) []batchv1.Job {
+	desired := []batchv1.Job{}
+	type builder func(context.Context, client.Object, models.Params) *batchv1.Job
+	for _, builder := range []builder{rotatePasswordJob} {
+		job := builder(ctx, instance, params)
+		// add only the non-nil to the list
+		if job != nil {
+			desired = append(desired, *job)
+		}
+	}
+	return desired
+}
+
+// rotatePasswordJob changes the root password inside the allinone database to the pending password of the secret,
+// the secret reconciler only switches the pods to it after this job succeeded.
+func rotatePasswordJob(ctx context.Context, obj client.Object, params models.Params) *batchv1.Job {
+	instance := obj.(*apolloiov1alpha1.Apollo)
+	if databaseMode(instance) == apolloiov1alpha1.DatabaseModeExternal {
+		return nil
+	}
+	// NOTE 只在secret暂存了新密码时运行，job的注解和暂存的轮换保持一致，轮换完成后job会被删除
+	secret := databaseSecret(ctx, instance, params)
+	if secret == nil || len(secret.Data[utils.SecretPendingPasswordKey]) == 0 {
+		return nil
+	}
+	token := secret.Annotations[utils.DBPasswordRotationAnnotation]
+
+	name := naming.AllInOneDBRotateJob(instance)
+	labels := utils.Labels(instance, name, []string{})
+	// NOTE 轮换完成后pending密码会被删除
+	pendingPassword := utils.SecretKeyRef(nil, naming.AllInOneDBSecret(instance), utils.SecretPendingPasswordKey)
+	pendingPassword.Optional = &[]bool{true}[0]
+	var backoffLimit int32 = 6
+
+	image, script := instance.Spec.Database.Image, rotateMysqlPasswordScript
+	if instance.Spec.Database.Type == apolloiov1alpha1.DatabaseTypePostgreSQL {
+		script = rotatePostgreSQLPasswordScript
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.GetNamespace(),
+			Labels:    labels,
+			Annotations: map[string]string{
+				utils.DBPasswordRotationAnnotation: token,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{utils.DatabaseClientLabel: "true"},
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyOnFailure,
+					Containers: []corev1.Container{
+						{
+							Name:  naming.Container(),
+							Image: image, // NOTE 和数据库的镜像保持一致
+							Env: []corev1.EnvVar{
+								{
+									Name:  "DB_HOST",
+									Value: fmt.Sprintf("%s.%s", naming.AllInOneDBService(instance), instance.Namespace),
+								},
+								{
+									Name:  "DB_PORT",
+									Value: strconv.Itoa(int(instance.Spec.Database.Type.DefaultPort())),
+								},
+								{
+									Name:      "NEW_PASSWORD",
+									ValueFrom: &corev1.EnvVarSource{SecretKeyRef: pendingPassword},
+								},
+								{
+									Name:      "CURRENT_PASSWORD",
+									ValueFrom: &corev1.EnvVarSource{SecretKeyRef: utils.SecretKeyRef(nil, naming.AllInOneDBSecret(instance), utils.SecretPasswordKey)},
+								},
+							},
+							Command: []string{"bash", "-c", script},
+						},
+					},
+				},
+			},
+		},
+	}
+	utils.ApplyRestrictedSecurity(&job.Spec.Template.Spec, utils.DatabaseUserID)
+	return job
+}
+
+// databaseConfigFile is the name of the extra my.cnf in the configmap and in /etc/mysql/conf.d.
+const databaseConfigFile = "apollo.cnf"
+
+// Scripts changing the root password of the allinone database to the pending one with the password in use.
+const (
+	rotateMysqlPasswordScript      = "set -e\n# Nothing to do without a pending password or if it is already in use.\nif [ -z \"${NEW_PASSWORD}\" ]; then exit 0; fi\nif mysql -h\"${DB_HOST}\" -P\"${DB_PORT}\" -uroot -p\"${NEW_PASSWORD}\" -e \"SELECT 1\" >/dev/null 2>&1; then exit 0; fi\n# Change the root password with the current one.\nmysql -h\"${DB_HOST}\" -P\"${DB_PORT}\" -uroot -p\"${CURRENT_PASSWORD}\" -e \"ALTER USER 'root'@'%' IDENTIFIED BY '${NEW_PASSWORD}'; ALTER USER 'root'@'localhost' IDENTIFIED BY '${NEW_PASSWORD}'; FLUSH PRIVILEGES;\""
+	rotatePostgreSQLPasswordScript = "set -e\n# Nothing to do without a pending password or if it is already in use.\nif [ -z \"${NEW_PASSWORD}\" ]; then exit 0; fi\nif PGPASSWORD=\"${NEW_PASSWORD}\" psql -h\"${DB_HOST}\" -p\"${DB_PORT}\" -Uroot -dpostgres -c \"SELECT 1\" >/dev/null 2>&1; then exit 0; fi\n# Change the root password with the current one.\nPGPASSWORD=\"${CURRENT_PASSWORD}\" psql -h\"${DB_HOST}\" -p\"${DB_PORT}\" -Uroot -dpostgres -c \"ALTER USER root WITH PASSWORD '${NEW_PASSWORD}'\""
+)
+
+// DesiredServiceAccounts 构建serviceaccount对象，使用已有serviceaccount的组件不构建
+func (o ApolloAllInOne) DesiredServiceAccounts(ctx context.Context, obj client.Object, params models.Params) []corev1.ServiceAccount {
+	instance := obj.(*apolloiov1alpha1.Apollo)
+	accounts := []*corev1.ServiceAccount{
+		utils.ServiceAccount(instance, naming.ConfigServiceAccount(instance), instance.Spec.ConfigService.ServiceAccount),
+		utils.ServiceAccount(instance, naming.AdminServiceAccount(instance), instance.Spec.AdminService.ServiceAccount),
+		utils.ServiceAccount(instance, naming.PortalServiceAccount(instance), instance.Spec.PortalService.ServiceAccount),
+	}
+	if databaseMode(instance) != apolloiov1alpha1.DatabaseModeExternal {
+		accounts = append(accounts, utils.ServiceAccount(instance, naming.DBServiceAccount(instance), instance.Spec.Database.ServiceAccount))
+	}
+	desired := []corev1.ServiceAccount{}
+	for _, account := range accounts {
+		if account != nil {
+			desired = append(desired, *account)
+		}
+	}
+	return desired
+}
+
+// DesiredHorizontalPodAutoscalers 构建hpa对象，只为启用了autoscaling的组件构建，数据库不会自动扩缩容
+func (o ApolloAllInOne) DesiredHorizontalPodAutoscalers(ctx context.Context, obj client.Object, params models.Params) []autoscalingv2.HorizontalPodAutoscaler {
+	instance := obj.(*apolloiov1alpha1.Apollo)
+	desired := []autoscalingv2.HorizontalPodAutoscaler{}
+	if instance.Spec.ConfigService.Autoscaling.Enabled {
+		desired = append(desired, utils.HorizontalPodAutoscaler(instance, naming.ConfigDeployment(instance), instance.Spec.ConfigService.Autoscaling))
+	}
+	if instance.Spec.AdminService.Autoscaling.Enabled {
+		desired = append(desired, utils.HorizontalPodAutoscaler(instance, naming.AdminDeployment(instance), instance.Spec.AdminService.Autoscaling))
+	}
+	if instance.Spec.PortalService.Autoscaling.Enabled {
+		desired = append(desired, utils.HorizontalPodAutoscaler(instance, naming.PortalDeployment(instance), instance.Spec.PortalService.Autoscaling))
+	}
+	return desired
+}
+
+// DesiredPodDisruptionBudgets 构建pdb对象，默认只为多副本的组件构建，单副本的数据库没有pdb
+func (o ApolloAllInOne) DesiredPodDisruptionBudgets(ctx context.Context, obj client.Object, params models.Params) []policyv1.PodDisruptionBudget {
+	instance := obj.(*apolloiov1alpha1.Apollo)
+	desired := []policyv1.PodDisruptionBudget{}
+	if pdb := utils.PodDisruptionBudget(instance, naming.ConfigDeployment(instance), utils.SelectorLabelsWithCustom(instance, map[string]string{"app": "configService"}),
+		instance.Spec.ConfigService.Replicas, instance.Spec.ConfigService.Autoscaling, instance.Spec.ConfigService.PodDisruptionBudget); pdb != nil {
+		desired = append(desired, *pdb)
+	}
+	if pdb := utils.PodDisruptionBudget(instance, naming.AdminDeployment(instance), utils.SelectorLabelsWithCustom(instance, map[string]string{"app": "adminService"}),
+		instance.Spec.AdminService.Replicas, instance.Spec.AdminService.Autoscaling, instance.Spec.AdminService.PodDisruptionBudget); pdb != nil {
+		desired = append(desired, *pdb)
+	}
+	if pdb := utils.PodDisruptionBudget(instance, naming.PortalDeployment(instance), utils.SelectorLabelsWithCustom(instance, map[string]string{"app": "portalService"}),
+		instance.Spec.PortalService.Replicas, instance.Spec.PortalService.Autoscaling, instance.Spec.PortalService.PodDisruptionBudget); pdb != nil {
+		desired = append(desired, *pdb)
+	}
+	return desired
+}
+
+// DesiredNetworkPolicies 构建networkpolicy对象，启用networkPolicy时才构建
+func (o ApolloAllInOne) DesiredNetworkPolicies(ctx context.Context, obj client.Object, params models.Params) []networkingv1.NetworkPolicy {
+	instance := obj.(*apolloiov1alpha1.Apollo)
+	policy := instance.Spec.NetworkPolicy
+	desired := []networkingv1.NetworkPolicy{}
+	if !policy.Enabled {
+		return desired
+	}
+
+	configPods := utils.SelectorLabelsWithCustom(instance, map[string]string{"app": "configService"})
+	adminPods := utils.SelectorLabelsWithCustom(instance, map[string]string{"app": "adminService"})
+	portalPods := utils.SelectorLabelsWithCustom(instance, map[string]string{"app": "portalService"})
+	if databaseMode(instance) != apolloiov1alpha1.DatabaseModeExternal {
+		desired = append(desired, utils.NetworkPolicy(instance, naming.AllInOneDBService(instance),
+			utils.SelectorLabelsWithCustom(instance, map[string]string{"app": "apollo-db"}), instance.Spec.Database.Type.DefaultPort(),
+			utils.DatabasePeers(configPods, adminPods, portalPods)))
+	}
+	return append(desired,
+		utils.NetworkPolicy(instance, naming.ConfigDeployment(instance), configPods, instance.Spec.ConfigService.ContainerPort,
+			utils.ConfigServicePeers(policy)),
+		utils.NetworkPolicy(instance, naming.AdminDeployment(instance), adminPods, instance.Spec.AdminService.ContainerPort,
+			utils.AdminServicePeers(policy, portalPods)),
+	)
+}
+
 // DesiredDeployments 构建deployment对象
 func (o ApolloAllInOne) DesiredDeployments(ctx context.Context, instance client.Object, params models.Params) []appsv1.Deployment {
 
 	desired := []appsv1.Deployment{}
 	type builder func(context.Context, client.Object, models.Params) *appsv1.Deployment
-	for _, builder := range []builder{configDeployment, adminDeployment, portalDeployment} {
+	for _, builder := range []builder{apollodbDeployment, configDeployment, adminDeployment, portalDeployment} {
 		deployment := builder(ctx, instance, params)
 		// add only the non-nil to the list
 		if deployment != nil {
@@ -481,11 +891,61 @@ func (o ApolloAllInOne) DesiredDeployments(ctx context.Context, instance client.
 	return desired
 }
 
+// apollodbDeployment runs the embedded database, its data lives in a memory emptyDir and is lost when the pod is restarted.
+func apollodbDeployment(ctx context.Context, obj client.Object, params models.Params) *appsv1.Deployment {
+	instance := obj.(*apolloiov1alpha1.Apollo)
+	if databaseMode(instance) != apolloiov1alpha1.DatabaseModeEmbedded {
+		return nil
+	}
+	name := naming.AllInOneDBDeployment(instance)
+	labels := utils.Labels(instance, name, []string{})
+
+	template := buildDatabasePodTemplate(ctx, instance)
+	if hasDatabaseConfig(instance) {
+		setConfigHash(ctx, instance, params, &template)
+	}
+	// NOTE 和数据库容器中挂载的数据目录保持一致
+	template.Spec.Volumes = append(template.Spec.Volumes, corev1.Volume{
+		Name: naming.AllInOnePVC(instance),
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory},
+		},
+	})
+
+	var replicas int32 = 1
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.GetNamespace(),
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: utils.SelectorLabelsWithCustom(instance, map[string]string{"app": "apollo-db"})},
+			Template: template,
+			// NOTE 数据不能在两个pod之间共享
+			Strategy: appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType},
+		},
+	}
+}
+
+// setConfigHash rolls the pods of a deployment when the content of its configmaps or secrets changes.
+func setConfigHash(ctx context.Context, instance client.Object, params models.Params, template *corev1.PodTemplateSpec) {
+	desired := ApolloAllInOne{}.DesiredConfigMaps(ctx, instance, params)
+	if err := utils.SetConfigHash(ctx, params.Client, instance.GetNamespace(), template, desired); err != nil {
+		params.Log.Error(err, "failed to compute the config hash")
+	}
+}
+
 func configDeployment(ctx context.Context, instance client.Object, params models.Params) *appsv1.Deployment {
 	name := naming.ConfigDeployment(instance) // TODO 调用allinone专门的 名字服务
 	labels := utils.Labels(instance, name, []string{})
 
 	spec, _ := buildConfigDepolymentSpec(ctx, instance)
+	setRotatedAnnotation(ctx, instance.(*apolloiov1alpha1.Apollo), params, &spec.Template)
+	if !instance.(*apolloiov1alpha1.Apollo).Spec.ConfigService.DisableConfigRollout {
+		setConfigHash(ctx, instance, params, &spec.Template)
+	}
 
 	configDeployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -517,8 +977,11 @@ func buildConfigDepolymentSpec(ctx context.Context, obj client.Object) (appsv1.D
 			Tolerations:      instance.Spec.ConfigService.Tolerations,
 		},
 	}
+	utils.WritableLogs(&template)
+	utils.ApplyPodTemplateOverrides(&template, instance.Spec.ConfigService.PodTemplate, utils.ApolloUserID)
+	utils.ApplyServiceAccount(&template, naming.ConfigServiceAccount(instance), instance.Spec.ConfigService.ServiceAccount)
 	return appsv1.DeploymentSpec{
-		Replicas: &instance.Spec.ConfigService.Replicas,
+		Replicas: utils.Replicas(instance, instance.Spec.ConfigService.Replicas, instance.Spec.ConfigService.Autoscaling),
 		Selector: &metav1.LabelSelector{MatchLabels: utils.SelectorLabelsWithCustom(instance, map[string]string{"app": "configService"})},
 		Strategy: instance.Spec.ConfigService.Strategy,
 		Template: template,
@@ -553,7 +1016,7 @@ func buildConfigContainer(ctx context.Context, instance *apolloiov1alpha1.Apollo
 				Protocol:      corev1.ProtocolTCP,
 			},
 		},
-		Env: append(instance.Spec.ConfigService.Env, corev1.EnvVar{
+		Env: append(append(datasourceEnv(instance), instance.Spec.ConfigService.Env...), corev1.EnvVar{
 			Name:  "SPRING_PROFILES_ACTIVE",
 			Value: instance.Spec.ConfigService.Config.Profiles,
 		}),
@@ -608,6 +1071,10 @@ func adminDeployment(ctx context.Context, instance client.Object, params models.
 	labels := utils.Labels(instance, name, []string{})
 
 	spec, _ := buildAdminDepolymentSpec(ctx, instance)
+	setRotatedAnnotation(ctx, instance.(*apolloiov1alpha1.Apollo), params, &spec.Template)
+	if !instance.(*apolloiov1alpha1.Apollo).Spec.AdminService.DisableConfigRollout {
+		setConfigHash(ctx, instance, params, &spec.Template)
+	}
 
 	adminDeployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -639,8 +1106,11 @@ func buildAdminDepolymentSpec(ctx context.Context, obj client.Object) (appsv1.De
 			Tolerations:      instance.Spec.AdminService.Tolerations,
 		},
 	}
+	utils.WritableLogs(&template)
+	utils.ApplyPodTemplateOverrides(&template, instance.Spec.AdminService.PodTemplate, utils.ApolloUserID)
+	utils.ApplyServiceAccount(&template, naming.AdminServiceAccount(instance), instance.Spec.AdminService.ServiceAccount)
 	return appsv1.DeploymentSpec{
-		Replicas: &instance.Spec.AdminService.Replicas,
+		Replicas: utils.Replicas(instance, instance.Spec.AdminService.Replicas, instance.Spec.AdminService.Autoscaling),
 		Selector: &metav1.LabelSelector{MatchLabels: utils.SelectorLabelsWithCustom(instance, map[string]string{"app": "adminService"})},
 		Strategy: instance.Spec.AdminService.Strategy,
 		Template: template,
@@ -675,7 +1145,7 @@ func buildAdminContainer(ctx context.Context, instance *apolloiov1alpha1.Apollo)
 				Protocol:      corev1.ProtocolTCP,
 			},
 		},
-		Env: append(instance.Spec.AdminService.Env, corev1.EnvVar{
+		Env: append(append(datasourceEnv(instance), instance.Spec.AdminService.Env...), corev1.EnvVar{
 			Name:  "SPRING_PROFILES_ACTIVE",
 			Value: instance.Spec.AdminService.Config.Profiles,
 		}),
@@ -732,6 +1202,10 @@ func portalDeployment(ctx context.Context, instance client.Object, params models
 	labels := utils.Labels(instance, name, []string{})
 
 	spec, _ := buildPortalDepolymentSpec(ctx, instance)
+	setRotatedAnnotation(ctx, instance.(*apolloiov1alpha1.Apollo), params, &spec.Template)
+	if !instance.(*apolloiov1alpha1.Apollo).Spec.PortalService.DisableConfigRollout {
+		setConfigHash(ctx, instance, params, &spec.Template)
+	}
 
 	portalDepolyment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -763,8 +1237,11 @@ func buildPortalDepolymentSpec(ctx context.Context, obj client.Object) (appsv1.D
 			Tolerations:      instance.Spec.PortalService.Tolerations,
 		},
 	}
+	utils.WritableLogs(&template)
+	utils.ApplyPodTemplateOverrides(&template, instance.Spec.PortalService.PodTemplate, utils.ApolloUserID)
+	utils.ApplyServiceAccount(&template, naming.PortalServiceAccount(instance), instance.Spec.PortalService.ServiceAccount)
 	return appsv1.DeploymentSpec{
-		Replicas: &instance.Spec.PortalService.Replicas,
+		Replicas: utils.Replicas(instance, instance.Spec.PortalService.Replicas, instance.Spec.PortalService.Autoscaling),
 		Selector: &metav1.LabelSelector{MatchLabels: utils.SelectorLabelsWithCustom(instance, map[string]string{"app": "portalService"})},
 		Strategy: instance.Spec.PortalService.Strategy,
 		Template: template,
@@ -812,7 +1289,7 @@ func buildPortalContainer(ctx context.Context, instance *apolloiov1alpha1.Apollo
 				Protocol:      corev1.ProtocolTCP,
 			},
 		},
-		Env: append(instance.Spec.PortalService.Env, corev1.EnvVar{
+		Env: append(append(datasourceEnv(instance), instance.Spec.PortalService.Env...), corev1.EnvVar{
 			Name:  "SPRING_PROFILES_ACTIVE",
 			Value: instance.Spec.PortalService.Config.Profiles,
 		}),
@@ -874,6 +1351,54 @@ func buildPortalProbe(ctx context.Context, instance *apolloiov1alpha1.Apollo) (l
 	return livenessProbe, readinessProbe, nil
 }
 
+// DesiredCertificates 构建cert-manager的certificate对象，只为设置了tlsIssuerRef且不使用注解的ingress构建
+func (o ApolloAllInOne) DesiredCertificates(ctx context.Context, obj client.Object, params models.Params) []unstructured.Unstructured {
+	instance := obj.(*apolloiov1alpha1.Apollo)
+	desired := []unstructured.Unstructured{}
+	for name, ingress := range map[string]apolloiov1alpha1.Ingress{
+		naming.ConfigIngress(instance): instance.Spec.ConfigService.Ingress,
+		naming.AdminIngress(instance):  instance.Spec.AdminService.Ingress,
+		naming.PortalIngress(instance): instance.Spec.PortalService.Ingress,
+	} {
+		if certificate := utils.Certificate(instance, name, ingress); certificate != nil {
+			desired = append(desired, *certificate)
+		}
+	}
+	return desired
+}
+
+// DesiredHTTPRoutes 构建Gateway API的httproute对象，只为exposure.type为gatewayAPI的组件构建
+func (o ApolloAllInOne) DesiredHTTPRoutes(ctx context.Context, obj client.Object, params models.Params) []unstructured.Unstructured {
+	instance := obj.(*apolloiov1alpha1.Apollo)
+	desired := []unstructured.Unstructured{}
+	for _, route := range []*unstructured.Unstructured{
+		utils.HTTPRoute(instance, naming.ConfigRoute(instance), instance.Spec.ConfigService.Exposure, instance.Spec.ConfigService.Ingress,
+			instance.Spec.ConfigService.Config.ContextPath, naming.ConfigService(instance), instance.Spec.ConfigService.Service.Port),
+		utils.HTTPRoute(instance, naming.AdminRoute(instance), instance.Spec.AdminService.Exposure, instance.Spec.AdminService.Ingress,
+			instance.Spec.AdminService.Config.ContextPath, naming.AdminService(instance), instance.Spec.AdminService.Service.Port),
+		utils.HTTPRoute(instance, naming.PortalRoute(instance), instance.Spec.PortalService.Exposure, instance.Spec.PortalService.Ingress,
+			instance.Spec.PortalService.Config.ContextPath, naming.PortalService(instance), instance.Spec.PortalService.Service.Port),
+	} {
+		if route != nil {
+			desired = append(desired, *route)
+		}
+	}
+	return desired
+}
+
+// DesiredRoutes 构建OpenShift的route对象，只为exposure.type为route的组件构建
+func (o ApolloAllInOne) DesiredRoutes(ctx context.Context, obj client.Object, params models.Params) []unstructured.Unstructured {
+	instance := obj.(*apolloiov1alpha1.Apollo)
+	desired := []unstructured.Unstructured{}
+	desired = append(desired, utils.Routes(instance, naming.ConfigRoute(instance), instance.Spec.ConfigService.Exposure, instance.Spec.ConfigService.Ingress,
+		naming.ConfigIngress(instance), instance.Spec.ConfigService.Config.ContextPath, naming.ConfigService(instance))...)
+	desired = append(desired, utils.Routes(instance, naming.AdminRoute(instance), instance.Spec.AdminService.Exposure, instance.Spec.AdminService.Ingress,
+		naming.AdminIngress(instance), instance.Spec.AdminService.Config.ContextPath, naming.AdminService(instance))...)
+	desired = append(desired, utils.Routes(instance, naming.PortalRoute(instance), instance.Spec.PortalService.Exposure, instance.Spec.PortalService.Ingress,
+		naming.PortalIngress(instance), instance.Spec.PortalService.Config.ContextPath, naming.PortalService(instance))...)
+	return desired
+}
+
 // DesiredIngresses 构建ingress对象
 func (o ApolloAllInOne) DesiredIngresses(ctx context.Context, instance client.Object, params models.Params) []networkingv1.Ingress {
 	desired := []networkingv1.Ingress{}
@@ -890,6 +1415,9 @@ func (o ApolloAllInOne) DesiredIngresses(ctx context.Context, instance client.Ob
 
 func configIngress(ctx context.Context, obj client.Object, params models.Params) *networkingv1.Ingress {
 	instance := obj.(*apolloiov1alpha1.Apollo)
+	if instance.Spec.ConfigService.Exposure.Type != apolloiov1alpha1.ExposureTypeIngress {
+		return nil
+	}
 	name := naming.ConfigIngress(instance)
 	labels := utils.Labels(instance, name, []string{})
 
@@ -899,7 +1427,7 @@ func configIngress(ctx context.Context, obj client.Object, params models.Params)
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        name,
 			Namespace:   instance.GetNamespace(),
-			Annotations: instance.Spec.ConfigService.Ingress.Annotations,
+			Annotations: utils.IngressAnnotations(instance.Spec.ConfigService.Ingress),
 			Labels:      labels,
 		},
 		Spec: spec,
@@ -913,7 +1441,7 @@ func buildConfigIngressSpec(ctx context.Context, instance *apolloiov1alpha1.Apol
 	}
 
 	return networkingv1.IngressSpec{
-		TLS:              instance.Spec.ConfigService.Ingress.TLS,
+		TLS:              utils.IngressTLS(naming.ConfigIngress(instance), instance.Spec.ConfigService.Ingress),
 		Rules:            rules,
 		IngressClassName: instance.Spec.ConfigService.Ingress.IngressClassName,
 	}, nil
@@ -946,6 +1474,9 @@ func buildConfigRule(instance *apolloiov1alpha1.Apollo, host string) networkingv
 
 func adminIngress(ctx context.Context, obj client.Object, params models.Params) *networkingv1.Ingress {
 	instance := obj.(*apolloiov1alpha1.Apollo)
+	if instance.Spec.AdminService.Exposure.Type != apolloiov1alpha1.ExposureTypeIngress {
+		return nil
+	}
 	name := naming.AdminIngress(instance)
 	labels := utils.Labels(instance, name, []string{})
 
@@ -955,7 +1486,7 @@ func adminIngress(ctx context.Context, obj client.Object, params models.Params)
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        name,
 			Namespace:   instance.GetNamespace(),
-			Annotations: instance.Spec.AdminService.Ingress.Annotations,
+			Annotations: utils.IngressAnnotations(instance.Spec.AdminService.Ingress),
 			Labels:      labels,
 		},
 		Spec: spec,
@@ -969,7 +1500,7 @@ func buildAdminIngressSpec(ctx context.Context, instance *apolloiov1alpha1.Apoll
 	}
 
 	return networkingv1.IngressSpec{
-		TLS:              instance.Spec.AdminService.Ingress.TLS,
+		TLS:              utils.IngressTLS(naming.AdminIngress(instance), instance.Spec.AdminService.Ingress),
 		Rules:            rules,
 		IngressClassName: instance.Spec.AdminService.Ingress.IngressClassName,
 	}, nil
@@ -1002,6 +1533,9 @@ func buildAdminRule(instance *apolloiov1alpha1.Apollo, host string) networkingv1
 
 func portalIngress(ctx context.Context, obj client.Object, params models.Params) *networkingv1.Ingress {
 	instance := obj.(*apolloiov1alpha1.Apollo)
+	if instance.Spec.PortalService.Exposure.Type != apolloiov1alpha1.ExposureTypeIngress {
+		return nil
+	}
 	name := naming.PortalIngress(instance)
 	labels := utils.Labels(instance, name, []string{})
 
@@ -1011,7 +1545,7 @@ func portalIngress(ctx context.Context, obj client.Object, params models.Params)
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        name,
 			Namespace:   instance.GetNamespace(),
-			Annotations: instance.Spec.ConfigService.Ingress.Annotations,
+			Annotations: utils.IngressAnnotations(instance.Spec.PortalService.Ingress),
 			Labels:      labels,
 		},
 		Spec: spec,
@@ -1025,7 +1559,7 @@ func buildPortalIngressSpec(ctx context.Context, instance *apolloiov1alpha1.Apol
 	}
 
 	return networkingv1.IngressSpec{
-		TLS:              instance.Spec.PortalService.Ingress.TLS,
+		TLS:              utils.IngressTLS(naming.PortalIngress(instance), instance.Spec.PortalService.Ingress),
 		Rules:            rules,
 		IngressClassName: instance.Spec.PortalService.Ingress.IngressClassName,
 	}, nil
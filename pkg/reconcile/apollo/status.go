@@ -0,0 +1,92 @@
+package apollo
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"apolloconfig.com/apollo-operator/pkg/schema"
+	"apolloconfig.com/apollo-operator/pkg/utils"
+	"apolloconfig.com/apollo-operator/pkg/utils/naming"
+	"context"
+	"fmt"
+	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// UpdateStatus 更新status子资源
+func (o ApolloAllInOne) UpdateStatus(ctx context.Context, obj client.Object, params models.Params) error {
+	instance := obj.(*apolloiov1alpha1.Apollo)
+	patch := client.MergeFrom(instance.DeepCopy())
+
+	configStatus, configDeployment, err := utils.DeploymentStatus(ctx, params.Client, instance.Namespace, naming.ConfigDeployment(instance),
+		utils.ServiceURL(naming.ConfigService(instance), instance.Namespace, instance.Spec.ConfigService.Service.Port, instance.Spec.ConfigService.Config.ContextPath))
+	if err != nil {
+		return err
+	}
+	if configStatus.Certificate, err = utils.CertificateStatus(ctx, params.Client, instance.Namespace, naming.ConfigIngress(instance), instance.Spec.ConfigService.Ingress); err != nil {
+		return err
+	}
+	adminStatus, adminDeployment, err := utils.DeploymentStatus(ctx, params.Client, instance.Namespace, naming.AdminDeployment(instance),
+		utils.ServiceURL(naming.AdminService(instance), instance.Namespace, instance.Spec.AdminService.Service.Port, instance.Spec.AdminService.Config.ContextPath))
+	if err != nil {
+		return err
+	}
+	if adminStatus.Certificate, err = utils.CertificateStatus(ctx, params.Client, instance.Namespace, naming.AdminIngress(instance), instance.Spec.AdminService.Ingress); err != nil {
+		return err
+	}
+	portalStatus, portalDeployment, err := utils.DeploymentStatus(ctx, params.Client, instance.Namespace, naming.PortalDeployment(instance),
+		utils.ServiceURL(naming.PortalService(instance), instance.Namespace, instance.Spec.PortalService.Service.Port, instance.Spec.PortalService.Config.ContextPath))
+	if err != nil {
+		return err
+	}
+	if portalStatus.Certificate, err = utils.CertificateStatus(ctx, params.Client, instance.Namespace, naming.PortalIngress(instance), instance.Spec.PortalService.Ingress); err != nil {
+		return err
+	}
+
+	instance.Status.ObservedGeneration = instance.Generation
+	instance.Status.ConfigService = configStatus
+	instance.Status.AdminService = adminStatus
+	instance.Status.PortalService = portalStatus
+	utils.SetStatusConditions(&instance.Status.Conditions, instance.Generation,
+		[]*appsv1.Deployment{configDeployment, adminDeployment, portalDeployment},
+		databaseReachable(ctx, instance, params))
+	utils.SetVersionStatus(&instance.Status.Version, schema.ImageVersion(instance.Spec.ConfigService.Image),
+		[]*appsv1.Deployment{configDeployment, adminDeployment, portalDeployment})
+
+	if err := params.Client.Status().Patch(ctx, instance, patch); err != nil {
+		return fmt.Errorf("failed to patch status: %w", err)
+	}
+	return nil
+}
+
+// databaseReachable checks the all-in-one database through the readiness of its statefulset or embedded deployment,
+// and an external database with a TCP connection.
+func databaseReachable(ctx context.Context, instance *apolloiov1alpha1.Apollo, params models.Params) error {
+	switch databaseMode(instance) {
+	case apolloiov1alpha1.DatabaseModeExternal:
+		return utils.CheckTCP(ctx, instance.Spec.Database.External.Host, instance.Spec.Database.External.Port)
+	case apolloiov1alpha1.DatabaseModeEmbedded:
+		_, deployment, err := utils.DeploymentStatus(ctx, params.Client, instance.Namespace, naming.AllInOneDBDeployment(instance), "")
+		if err != nil {
+			return err
+		}
+		if deployment == nil || deployment.Status.ReadyReplicas < 1 {
+			return fmt.Errorf("deployment %s has no ready replicas", naming.AllInOneDBDeployment(instance))
+		}
+		return nil
+	}
+
+	statefulSet := &appsv1.StatefulSet{}
+	nns := types.NamespacedName{Namespace: instance.Namespace, Name: naming.AllInOneStatefulSet(instance)}
+	if err := params.Client.Get(ctx, nns, statefulSet); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return fmt.Errorf("statefulset %s has not been created yet", nns.Name)
+		}
+		return err
+	}
+	if statefulSet.Status.ReadyReplicas < 1 {
+		return fmt.Errorf("statefulset %s has no ready replicas", nns.Name)
+	}
+	return nil
+}
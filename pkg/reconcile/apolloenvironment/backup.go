@@ -0,0 +1,21 @@
+package apolloenvironment
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	"apolloconfig.com/apollo-operator/pkg/utils"
+	"apolloconfig.com/apollo-operator/pkg/utils/naming"
+	"fmt"
+)
+
+// Databases returns the ConfigDB of the instance, as dumped by an ApolloBackup.
+func Databases(instance *apolloiov1alpha1.ApolloEnvironment) []utils.Database {
+	return []utils.Database{
+		{
+			Host:     fmt.Sprintf("%s.%s", naming.ConfigDBService(instance), instance.Namespace),
+			Port:     instance.Spec.ConfigDB.Service.Port,
+			Name:     instance.Spec.ConfigDB.DBName,
+			Username: utils.SecretKeyRef(instance.Spec.ConfigDB.UsernameSecretRef, naming.ConfigDBSecret(instance), utils.SecretUsernameKey),
+			Password: utils.SecretKeyRef(instance.Spec.ConfigDB.PasswordSecretRef, naming.ConfigDBSecret(instance), utils.SecretPasswordKey),
+		},
+	}
+}
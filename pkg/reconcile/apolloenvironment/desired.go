@@ -3,14 +3,19 @@ package apolloenvironment
 import (
 	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
 	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"apolloconfig.com/apollo-operator/pkg/schema"
 	"apolloconfig.com/apollo-operator/pkg/utils"
 	"apolloconfig.com/apollo-operator/pkg/utils/naming"
 	"context"
 	"fmt"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"strings"
@@ -20,7 +25,7 @@ import (
 func (o ApolloEnvironment) DesiredConfigMaps(ctx context.Context, instance client.Object, params models.Params) []corev1.ConfigMap {
 	desired := []corev1.ConfigMap{}
 	type builder func(context.Context, client.Object, models.Params) *corev1.ConfigMap
-	for _, builder := range []builder{adminServiceConfig, configServiceConfig} {
+	for _, builder := range []builder{adminServiceConfig, configServiceConfig, configdbSchemaConfig} {
 		cm := builder(ctx, instance, params)
 		// add only the non-nil to the list
 		if cm != nil {
@@ -41,17 +46,13 @@ func configServiceConfig(_ context.Context, obj client.Object, params models.Par
 	data := map[string]string{}
 
 	// application-github.properties
-	// TODO 多种数据库支持
-	apolloGithubConfig := []string{
-		fmt.Sprintf("spring.datasource.username = %s", instance.Spec.ConfigDB.Username),
-		fmt.Sprintf("spring.datasource.password = %s", instance.Spec.ConfigDB.Password),
-		fmt.Sprintf("spring.datasource.url = jdbc:mysql://%s.%s:%d/%s?%s",
-			naming.ConfigDBService(instance), // NOTE 一定要确保和configdb服务名一致
-			instance.Namespace,               // NOTE 一定要确保和configdb服务的命名空间一致
-			instance.Spec.ConfigDB.Service.Port,
-			instance.Spec.ConfigDB.DBName,
-			instance.Spec.ConfigDB.ConnectionStringProperties),
-
+	// NOTE 用户名和密码通过环境变量从secret中注入，不写入configmap
+	apolloGithubConfig := utils.DatasourceProperties(instance.Spec.ConfigDB.Type,
+		fmt.Sprintf("%s.%s", naming.ConfigDBService(instance), instance.Namespace), // NOTE 一定要确保和configdb服务名及命名空间一致
+		instance.Spec.ConfigDB.Service.Port,
+		instance.Spec.ConfigDB.DBName,
+		instance.Spec.ConfigDB.ConnectionStringProperties)
+	apolloGithubConfig = append(apolloGithubConfig,
 		// TODO 这里先默认k8s提供的服务发现地址
 		fmt.Sprintf("apollo.config-service.url = http://%s.%s:%d%s",
 			naming.ConfigService(instance), // NOTE 一定要确保和configService服务名一致
@@ -63,7 +64,7 @@ func configServiceConfig(_ context.Context, obj client.Object, params models.Par
 			instance.Namespace,            // NOTE 一定要确保和configService服务的命名空间一致
 			instance.Spec.AdminService.Service.Port,
 			instance.Spec.AdminService.Config.ContextPath),
-	}
+	)
 
 	if instance.Spec.ConfigService.Config.ContextPath != "" {
 		apolloGithubConfig = append(apolloGithubConfig, fmt.Sprintf("server.servlet.context-path = %s", instance.Spec.ConfigService.Config.ContextPath))
@@ -99,17 +100,12 @@ func adminServiceConfig(_ context.Context, obj client.Object, params models.Para
 	data := map[string]string{}
 
 	// application-github.properties
-	// TODO 多种数据库支持
-	apolloGithubConfig := []string{
-		fmt.Sprintf("spring.datasource.username = %s", instance.Spec.ConfigDB.Username),
-		fmt.Sprintf("spring.datasource.password = %s", instance.Spec.ConfigDB.Password),
-		fmt.Sprintf("spring.datasource.url = jdbc:mysql://%s.%s:%d/%s?%s",
-			naming.ConfigDBService(instance), // NOTE 一定要确保和configdb服务名一致
-			instance.Namespace,               // NOTE 一定要确保和configdb服务的命名空间一致
-			instance.Spec.ConfigDB.Service.Port,
-			instance.Spec.ConfigDB.DBName,
-			instance.Spec.ConfigDB.ConnectionStringProperties),
-	}
+	// NOTE 用户名和密码通过环境变量从secret中注入，不写入configmap
+	apolloGithubConfig := utils.DatasourceProperties(instance.Spec.ConfigDB.Type,
+		fmt.Sprintf("%s.%s", naming.ConfigDBService(instance), instance.Namespace), // NOTE 一定要确保和configdb服务名及命名空间一致
+		instance.Spec.ConfigDB.Service.Port,
+		instance.Spec.ConfigDB.DBName,
+		instance.Spec.ConfigDB.ConnectionStringProperties)
 
 	if instance.Spec.AdminService.Config.ContextPath != "" {
 		apolloGithubConfig = append(apolloGithubConfig, fmt.Sprintf("server.servlet.context-path = %s", instance.Spec.ConfigService.Config.ContextPath))
@@ -134,6 +130,58 @@ func adminServiceConfig(_ context.Context, obj client.Object, params models.Para
 
 }
 
+// DesiredSecrets 构建secret对象
+func (o ApolloEnvironment) DesiredSecrets(ctx context.Context, instance client.Object, params models.Params) []corev1.Secret {
+	desired := []corev1.Secret{}
+	type builder func(context.Context, client.Object, models.Params) *corev1.Secret
+	for _, builder := range []builder{configdbSecret} {
+		secret := builder(ctx, instance, params)
+		// add only the non-nil to the list
+		if secret != nil {
+			desired = append(desired, *secret)
+		}
+	}
+	return desired
+}
+
+// configdbSecret holds the configdb credentials that are not referenced from a user provided secret.
+func configdbSecret(_ context.Context, obj client.Object, params models.Params) *corev1.Secret {
+	instance := obj.(*apolloiov1alpha1.ApolloEnvironment)
+
+	data := map[string][]byte{}
+	if instance.Spec.ConfigDB.UsernameSecretRef == nil {
+		data[utils.SecretUsernameKey] = []byte(instance.Spec.ConfigDB.Username)
+	}
+	if instance.Spec.ConfigDB.PasswordSecretRef == nil {
+		data[utils.SecretPasswordKey] = []byte(instance.Spec.ConfigDB.Password)
+	}
+	// NOTE 用户名和密码都引用了用户的secret时，不需要operator生成secret
+	if len(data) == 0 {
+		return nil
+	}
+
+	name := naming.ConfigDBSecret(instance)
+	labels := utils.Labels(instance, name, []string{})
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.GetNamespace(),
+			Labels:    labels,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+}
+
+// datasourceEnv injects the configdb credentials from the user provided or the operator managed secret.
+func datasourceEnv(instance *apolloiov1alpha1.ApolloEnvironment) []corev1.EnvVar {
+	return utils.DatasourceEnv(
+		utils.SecretKeyRef(instance.Spec.ConfigDB.UsernameSecretRef, naming.ConfigDBSecret(instance), utils.SecretUsernameKey),
+		utils.SecretKeyRef(instance.Spec.ConfigDB.PasswordSecretRef, naming.ConfigDBSecret(instance), utils.SecretPasswordKey),
+	)
+}
+
 // DesiredEndpoints 构建endpoints对象
 func (o ApolloEnvironment) DesiredEndpoints(ctx context.Context, instance client.Object, params models.Params) []corev1.Endpoints {
 	// TODO 目前需求只有一个subset，后续可以拓展为多个
@@ -281,6 +329,183 @@ func headlessService(ctx context.Context, instance client.Object, params models.
 	return h
 }
 
+// configdbSchemaConfig holds the embedded ConfigDB scripts mounted by the schema job.
+func configdbSchemaConfig(_ context.Context, obj client.Object, params models.Params) *corev1.ConfigMap {
+	instance := obj.(*apolloiov1alpha1.ApolloEnvironment)
+	if !instance.Spec.ConfigDB.Schema.Manage {
+		return nil
+	}
+
+	data, err := schema.Scripts(schema.ConfigDB, schemaVersion(instance))
+	if err != nil {
+		// NOTE 错误会在WaitForSchema中写入status
+		return nil
+	}
+
+	name := naming.ConfigDBSchemaConfigMap(instance)
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.GetNamespace(),
+			Labels:    utils.Labels(instance, name, []string{}),
+		},
+		Data: data,
+	}
+}
+
+// schemaVersion returns the ConfigDB schema version matching the images of the instance.
+func schemaVersion(instance *apolloiov1alpha1.ApolloEnvironment) string {
+	return schema.ImageVersion(instance.Spec.ConfigService.Image)
+}
+
+// DesiredJobs 构建job对象
+func (o ApolloEnvironment) DesiredJobs(ctx context.Context, instance client.Object, params models.Params) []batchv1.Job {
+	desired := []batchv1.Job{}
+	type builder func(context.Context, client.Object, models.Params) *batchv1.Job
+	for _, builder := range []builder{configdbSchemaJob} {
+		job := builder(ctx, instance, params)
+		// add only the non-nil to the list
+		if job != nil {
+			desired = append(desired, *job)
+		}
+	}
+	return desired
+}
+
+// configdbSchemaJob creates or migrates the ConfigDB schema to the version of the images.
+func configdbSchemaJob(_ context.Context, obj client.Object, params models.Params) *batchv1.Job {
+	instance := obj.(*apolloiov1alpha1.ApolloEnvironment)
+	if !instance.Spec.ConfigDB.Schema.Manage {
+		return nil
+	}
+
+	version := schemaVersion(instance)
+	versions, err := schema.Versions(schema.ConfigDB, version)
+	if err != nil {
+		// NOTE 错误会在WaitForSchema中写入status
+		return nil
+	}
+
+	name := naming.ConfigDBSchemaJob(instance)
+	var backoffLimit int32 = 6
+	var defaultMode int32 = 420
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.GetNamespace(),
+			Labels:    utils.Labels(instance, name, []string{}),
+			Annotations: map[string]string{
+				utils.SchemaVersionAnnotation: version,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyOnFailure,
+					Containers: []corev1.Container{
+						{
+							Name:    naming.Container(),
+							Image:   instance.Spec.ConfigDB.Type.DefaultImage(), // NOTE 使用数据库类型的客户端
+							Command: []string{"bash", "-c", schema.Command},
+							Env: schema.Env(
+								fmt.Sprintf("%s.%s", naming.ConfigDBService(instance), instance.Namespace),
+								instance.Spec.ConfigDB.Service.Port,
+								instance.Spec.ConfigDB.DBName,
+								version,
+								versions,
+								utils.SecretKeyRef(instance.Spec.ConfigDB.UsernameSecretRef, naming.ConfigDBSecret(instance), utils.SecretUsernameKey),
+								utils.SecretKeyRef(instance.Spec.ConfigDB.PasswordSecretRef, naming.ConfigDBSecret(instance), utils.SecretPasswordKey)),
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      naming.ConfigDBSchemaConfigMap(instance),
+									MountPath: schema.MountPath,
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: naming.ConfigDBSchemaConfigMap(instance),
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{Name: naming.ConfigDBSchemaConfigMap(instance)}, // NOTE 和configmap的名字保持一致
+									DefaultMode:          &defaultMode,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	// NOTE schema job使用数据库镜像的客户端
+	utils.ApplyRestrictedSecurity(&job.Spec.Template.Spec, utils.DatabaseUserID)
+	return job
+}
+
+// DesiredServiceAccounts 构建serviceaccount对象，使用已有serviceaccount的组件不构建
+func (o ApolloEnvironment) DesiredServiceAccounts(ctx context.Context, obj client.Object, params models.Params) []corev1.ServiceAccount {
+	instance := obj.(*apolloiov1alpha1.ApolloEnvironment)
+	accounts := []*corev1.ServiceAccount{
+		utils.ServiceAccount(instance, naming.ConfigServiceAccount(instance), instance.Spec.ConfigService.ServiceAccount),
+		utils.ServiceAccount(instance, naming.AdminServiceAccount(instance), instance.Spec.AdminService.ServiceAccount),
+	}
+	desired := []corev1.ServiceAccount{}
+	for _, account := range accounts {
+		if account != nil {
+			desired = append(desired, *account)
+		}
+	}
+	return desired
+}
+
+// DesiredHorizontalPodAutoscalers 构建hpa对象，只为启用了autoscaling的组件构建
+func (o ApolloEnvironment) DesiredHorizontalPodAutoscalers(ctx context.Context, obj client.Object, params models.Params) []autoscalingv2.HorizontalPodAutoscaler {
+	instance := obj.(*apolloiov1alpha1.ApolloEnvironment)
+	desired := []autoscalingv2.HorizontalPodAutoscaler{}
+	if instance.Spec.ConfigService.Autoscaling.Enabled {
+		desired = append(desired, utils.HorizontalPodAutoscaler(instance, naming.ConfigDeployment(instance), instance.Spec.ConfigService.Autoscaling))
+	}
+	if instance.Spec.AdminService.Autoscaling.Enabled {
+		desired = append(desired, utils.HorizontalPodAutoscaler(instance, naming.AdminDeployment(instance), instance.Spec.AdminService.Autoscaling))
+	}
+	return desired
+}
+
+// DesiredPodDisruptionBudgets 构建pdb对象，默认只为多副本的组件构建
+func (o ApolloEnvironment) DesiredPodDisruptionBudgets(ctx context.Context, obj client.Object, params models.Params) []policyv1.PodDisruptionBudget {
+	instance := obj.(*apolloiov1alpha1.ApolloEnvironment)
+	desired := []policyv1.PodDisruptionBudget{}
+	if pdb := utils.PodDisruptionBudget(instance, naming.ConfigDeployment(instance), utils.SelectorLabelsWithCustom(instance, map[string]string{"app": "configService"}),
+		instance.Spec.ConfigService.Replicas, instance.Spec.ConfigService.Autoscaling, instance.Spec.ConfigService.PodDisruptionBudget); pdb != nil {
+		desired = append(desired, *pdb)
+	}
+	if pdb := utils.PodDisruptionBudget(instance, naming.AdminDeployment(instance), utils.SelectorLabelsWithCustom(instance, map[string]string{"app": "adminService"}),
+		instance.Spec.AdminService.Replicas, instance.Spec.AdminService.Autoscaling, instance.Spec.AdminService.PodDisruptionBudget); pdb != nil {
+		desired = append(desired, *pdb)
+	}
+	return desired
+}
+
+// DesiredNetworkPolicies 构建networkpolicy对象，启用networkPolicy时才构建；configdb在集群外，不需要隔离
+func (o ApolloEnvironment) DesiredNetworkPolicies(ctx context.Context, obj client.Object, params models.Params) []networkingv1.NetworkPolicy {
+	instance := obj.(*apolloiov1alpha1.ApolloEnvironment)
+	policy := instance.Spec.NetworkPolicy
+	if !policy.Enabled {
+		return []networkingv1.NetworkPolicy{}
+	}
+	return []networkingv1.NetworkPolicy{
+		utils.NetworkPolicy(instance, naming.ConfigDeployment(instance),
+			utils.SelectorLabelsWithCustom(instance, map[string]string{"app": "configService"}), instance.Spec.ConfigService.ContainerPort,
+			utils.ConfigServicePeers(policy)),
+		utils.NetworkPolicy(instance, naming.AdminDeployment(instance),
+			utils.SelectorLabelsWithCustom(instance, map[string]string{"app": "adminService"}), instance.Spec.AdminService.ContainerPort,
+			utils.AdminServicePeers(policy)),
+	}
+}
+
 // DesiredDeployments 构建deployment对象
 func (o ApolloEnvironment) DesiredDeployments(ctx context.Context, instance client.Object, params models.Params) []appsv1.Deployment {
 
@@ -296,11 +521,22 @@ func (o ApolloEnvironment) DesiredDeployments(ctx context.Context, instance clie
 	return desired
 }
 
+// setConfigHash rolls the pods of a deployment when the content of its configmaps or secrets changes.
+func setConfigHash(ctx context.Context, instance client.Object, params models.Params, template *corev1.PodTemplateSpec) {
+	desired := ApolloEnvironment{}.DesiredConfigMaps(ctx, instance, params)
+	if err := utils.SetConfigHash(ctx, params.Client, instance.GetNamespace(), template, desired); err != nil {
+		params.Log.Error(err, "failed to compute the config hash")
+	}
+}
+
 func configDeployment(ctx context.Context, instance client.Object, params models.Params) *appsv1.Deployment {
 	name := naming.ConfigDeployment(instance)
 	labels := utils.Labels(instance, name, []string{})
 
 	spec, _ := buildConfigDepolymentSpec(ctx, instance)
+	if !instance.(*apolloiov1alpha1.ApolloEnvironment).Spec.ConfigService.DisableConfigRollout {
+		setConfigHash(ctx, instance, params, &spec.Template)
+	}
 
 	configDeployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -332,8 +568,11 @@ func buildConfigDepolymentSpec(ctx context.Context, obj client.Object) (appsv1.D
 			Tolerations:      instance.Spec.ConfigService.Tolerations,
 		},
 	}
+	utils.WritableLogs(&template)
+	utils.ApplyPodTemplateOverrides(&template, instance.Spec.ConfigService.PodTemplate, utils.ApolloUserID)
+	utils.ApplyServiceAccount(&template, naming.ConfigServiceAccount(instance), instance.Spec.ConfigService.ServiceAccount)
 	return appsv1.DeploymentSpec{
-		Replicas: &instance.Spec.ConfigService.Replicas,
+		Replicas: utils.Replicas(instance, instance.Spec.ConfigService.Replicas, instance.Spec.ConfigService.Autoscaling),
 		Selector: &metav1.LabelSelector{MatchLabels: utils.SelectorLabelsWithCustom(instance, map[string]string{"app": "configService"})},
 		Strategy: instance.Spec.ConfigService.Strategy,
 		Template: template,
@@ -368,7 +607,7 @@ func buildConfigContainer(ctx context.Context, instance *apolloiov1alpha1.Apollo
 				Protocol:      corev1.ProtocolTCP,
 			},
 		},
-		Env: append(instance.Spec.ConfigService.Env, corev1.EnvVar{
+		Env: append(append(datasourceEnv(instance), instance.Spec.ConfigService.Env...), corev1.EnvVar{
 			Name:  "SPRING_PROFILES_ACTIVE",
 			Value: instance.Spec.ConfigService.Config.Profiles,
 		}),
@@ -423,6 +662,9 @@ func adminDeployment(ctx context.Context, instance client.Object, params models.
 	labels := utils.Labels(instance, name, []string{})
 
 	spec, _ := buildAdminDepolymentSpec(ctx, instance)
+	if !instance.(*apolloiov1alpha1.ApolloEnvironment).Spec.AdminService.DisableConfigRollout {
+		setConfigHash(ctx, instance, params, &spec.Template)
+	}
 
 	adminDeployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -454,8 +696,11 @@ func buildAdminDepolymentSpec(ctx context.Context, obj client.Object) (appsv1.De
 			Tolerations:      instance.Spec.AdminService.Tolerations,
 		},
 	}
+	utils.WritableLogs(&template)
+	utils.ApplyPodTemplateOverrides(&template, instance.Spec.AdminService.PodTemplate, utils.ApolloUserID)
+	utils.ApplyServiceAccount(&template, naming.AdminServiceAccount(instance), instance.Spec.AdminService.ServiceAccount)
 	return appsv1.DeploymentSpec{
-		Replicas: &instance.Spec.AdminService.Replicas,
+		Replicas: utils.Replicas(instance, instance.Spec.AdminService.Replicas, instance.Spec.AdminService.Autoscaling),
 		Selector: &metav1.LabelSelector{MatchLabels: utils.SelectorLabelsWithCustom(instance, map[string]string{"app": "adminService"})},
 		Strategy: instance.Spec.AdminService.Strategy,
 		Template: template,
@@ -490,7 +735,7 @@ func buildAdminContainer(ctx context.Context, instance *apolloiov1alpha1.ApolloE
 				Protocol:      corev1.ProtocolTCP,
 			},
 		},
-		Env: append(instance.Spec.AdminService.Env, corev1.EnvVar{
+		Env: append(append(datasourceEnv(instance), instance.Spec.AdminService.Env...), corev1.EnvVar{
 			Name:  "SPRING_PROFILES_ACTIVE",
 			Value: instance.Spec.AdminService.Config.Profiles,
 		}),
@@ -542,6 +787,49 @@ func buildAdminProbe(ctx context.Context, instance *apolloiov1alpha1.ApolloEnvir
 	return livenessProbe, readinessProbe, nil
 }
 
+// DesiredCertificates 构建cert-manager的certificate对象，只为设置了tlsIssuerRef且不使用注解的ingress构建
+func (o ApolloEnvironment) DesiredCertificates(ctx context.Context, obj client.Object, params models.Params) []unstructured.Unstructured {
+	instance := obj.(*apolloiov1alpha1.ApolloEnvironment)
+	desired := []unstructured.Unstructured{}
+	for name, ingress := range map[string]apolloiov1alpha1.Ingress{
+		naming.ConfigIngress(instance): instance.Spec.ConfigService.Ingress,
+		naming.AdminIngress(instance):  instance.Spec.AdminService.Ingress,
+	} {
+		if certificate := utils.Certificate(instance, name, ingress); certificate != nil {
+			desired = append(desired, *certificate)
+		}
+	}
+	return desired
+}
+
+// DesiredHTTPRoutes 构建Gateway API的httproute对象，只为exposure.type为gatewayAPI的组件构建
+func (o ApolloEnvironment) DesiredHTTPRoutes(ctx context.Context, obj client.Object, params models.Params) []unstructured.Unstructured {
+	instance := obj.(*apolloiov1alpha1.ApolloEnvironment)
+	desired := []unstructured.Unstructured{}
+	for _, route := range []*unstructured.Unstructured{
+		utils.HTTPRoute(instance, naming.ConfigRoute(instance), instance.Spec.ConfigService.Exposure, instance.Spec.ConfigService.Ingress,
+			instance.Spec.ConfigService.Config.ContextPath, naming.ConfigService(instance), instance.Spec.ConfigService.Service.Port),
+		utils.HTTPRoute(instance, naming.AdminRoute(instance), instance.Spec.AdminService.Exposure, instance.Spec.AdminService.Ingress,
+			instance.Spec.AdminService.Config.ContextPath, naming.AdminService(instance), instance.Spec.AdminService.Service.Port),
+	} {
+		if route != nil {
+			desired = append(desired, *route)
+		}
+	}
+	return desired
+}
+
+// DesiredRoutes 构建OpenShift的route对象，只为exposure.type为route的组件构建
+func (o ApolloEnvironment) DesiredRoutes(ctx context.Context, obj client.Object, params models.Params) []unstructured.Unstructured {
+	instance := obj.(*apolloiov1alpha1.ApolloEnvironment)
+	desired := []unstructured.Unstructured{}
+	desired = append(desired, utils.Routes(instance, naming.ConfigRoute(instance), instance.Spec.ConfigService.Exposure, instance.Spec.ConfigService.Ingress,
+		naming.ConfigIngress(instance), instance.Spec.ConfigService.Config.ContextPath, naming.ConfigService(instance))...)
+	desired = append(desired, utils.Routes(instance, naming.AdminRoute(instance), instance.Spec.AdminService.Exposure, instance.Spec.AdminService.Ingress,
+		naming.AdminIngress(instance), instance.Spec.AdminService.Config.ContextPath, naming.AdminService(instance))...)
+	return desired
+}
+
 // DesiredIngresses 构建ingress对象
 func (o ApolloEnvironment) DesiredIngresses(ctx context.Context, instance client.Object, params models.Params) []networkingv1.Ingress {
 	desired := []networkingv1.Ingress{}
@@ -558,6 +846,9 @@ func (o ApolloEnvironment) DesiredIngresses(ctx context.Context, instance client
 
 func configIngress(ctx context.Context, obj client.Object, params models.Params) *networkingv1.Ingress {
 	instance := obj.(*apolloiov1alpha1.ApolloEnvironment)
+	if instance.Spec.ConfigService.Exposure.Type != apolloiov1alpha1.ExposureTypeIngress {
+		return nil
+	}
 	name := naming.ConfigIngress(instance)
 	labels := utils.Labels(instance, name, []string{})
 
@@ -567,7 +858,7 @@ func configIngress(ctx context.Context, obj client.Object, params models.Params)
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        name,
 			Namespace:   instance.GetNamespace(),
-			Annotations: instance.Spec.ConfigService.Ingress.Annotations,
+			Annotations: utils.IngressAnnotations(instance.Spec.ConfigService.Ingress),
 			Labels:      labels,
 		},
 		Spec: spec,
@@ -581,7 +872,7 @@ func buildConfigIngressSpec(ctx context.Context, instance *apolloiov1alpha1.Apol
 	}
 
 	return networkingv1.IngressSpec{
-		TLS:              instance.Spec.ConfigService.Ingress.TLS,
+		TLS:              utils.IngressTLS(naming.ConfigIngress(instance), instance.Spec.ConfigService.Ingress),
 		Rules:            rules,
 		IngressClassName: instance.Spec.ConfigService.Ingress.IngressClassName,
 	}, nil
@@ -614,6 +905,9 @@ func buildConfigRule(instance *apolloiov1alpha1.ApolloEnvironment, host string)
 
 func adminIngress(ctx context.Context, obj client.Object, params models.Params) *networkingv1.Ingress {
 	instance := obj.(*apolloiov1alpha1.ApolloEnvironment)
+	if instance.Spec.AdminService.Exposure.Type != apolloiov1alpha1.ExposureTypeIngress {
+		return nil
+	}
 	name := naming.AdminIngress(instance)
 	labels := utils.Labels(instance, name, []string{})
 
@@ -623,7 +917,7 @@ func adminIngress(ctx context.Context, obj client.Object, params models.Params)
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        name,
 			Namespace:   instance.GetNamespace(),
-			Annotations: instance.Spec.AdminService.Ingress.Annotations,
+			Annotations: utils.IngressAnnotations(instance.Spec.AdminService.Ingress),
 			Labels:      labels,
 		},
 		Spec: spec,
@@ -637,7 +931,7 @@ func buildAdminIngressSpec(ctx context.Context, instance *apolloiov1alpha1.Apoll
 	}
 
 	return networkingv1.IngressSpec{
-		TLS:              instance.Spec.AdminService.Ingress.TLS,
+		TLS:              utils.IngressTLS(naming.AdminIngress(instance), instance.Spec.AdminService.Ingress),
 		Rules:            rules,
 		IngressClassName: instance.Spec.AdminService.Ingress.IngressClassName,
 	}, nil
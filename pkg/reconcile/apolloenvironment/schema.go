@@ -0,0 +1,62 @@
+package apolloenvironment
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"apolloconfig.com/apollo-operator/pkg/schema"
+	"apolloconfig.com/apollo-operator/pkg/utils"
+	"apolloconfig.com/apollo-operator/pkg/utils/naming"
+	"context"
+	"fmt"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WaitForSchema 记录ConfigDB schema job的状态，job成功之前返回错误，阻止deployment滚动更新
+func (o ApolloEnvironment) WaitForSchema(ctx context.Context, obj client.Object, params models.Params) error {
+	instance := obj.(*apolloiov1alpha1.ApolloEnvironment)
+	// NOTE 在副本上更新status，patch的返回值不会覆盖后续task使用的instance
+	current := instance.DeepCopy()
+	patch := client.MergeFrom(instance)
+
+	if !instance.Spec.ConfigDB.Schema.Manage {
+		if meta.FindStatusCondition(current.Status.Conditions, apolloiov1alpha1.ConditionSchemaReady) != nil {
+			meta.RemoveStatusCondition(&current.Status.Conditions, apolloiov1alpha1.ConditionSchemaReady)
+			if err := params.Client.Status().Patch(ctx, current, patch); err != nil {
+				return fmt.Errorf("failed to patch status: %w", err)
+			}
+			instance.Status = current.Status
+		}
+		return nil
+	}
+
+	version := schemaVersion(instance)
+	condition := metav1.Condition{
+		Type:   apolloiov1alpha1.ConditionSchemaReady,
+		Status: metav1.ConditionFalse,
+		Reason: apolloiov1alpha1.ReasonSchemaFailed,
+	}
+	ready := false
+	if _, err := schema.Versions(schema.ConfigDB, version); err != nil {
+		condition.Message = err.Error()
+	} else if condition, ready, err = utils.SchemaJobCondition(ctx, params.Client, instance.Namespace, naming.ConfigDBSchemaJob(instance), version); err != nil {
+		return err
+	}
+
+	condition.ObservedGeneration = instance.Generation
+	meta.SetStatusCondition(&current.Status.Conditions, condition)
+	if ready {
+		current.Status.Schema.Version = version
+	}
+	if err := params.Client.Status().Patch(ctx, current, patch); err != nil {
+		return fmt.Errorf("failed to patch status: %w", err)
+	}
+	// NOTE 只同步status，UpdateStatus的merge patch会整体替换conditions
+	instance.Status = current.Status
+
+	if !ready {
+		return fmt.Errorf("the deployments are held back until the schema is ready: %s", condition.Message)
+	}
+	return nil
+}
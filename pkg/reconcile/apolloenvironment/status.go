@@ -0,0 +1,48 @@
+package apolloenvironment
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"apolloconfig.com/apollo-operator/pkg/utils"
+	"apolloconfig.com/apollo-operator/pkg/utils/naming"
+	"context"
+	"fmt"
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// UpdateStatus 更新status子资源
+func (o ApolloEnvironment) UpdateStatus(ctx context.Context, obj client.Object, params models.Params) error {
+	instance := obj.(*apolloiov1alpha1.ApolloEnvironment)
+	patch := client.MergeFrom(instance.DeepCopy())
+
+	configStatus, configDeployment, err := utils.DeploymentStatus(ctx, params.Client, instance.Namespace, naming.ConfigDeployment(instance),
+		utils.ServiceURL(naming.ConfigService(instance), instance.Namespace, instance.Spec.ConfigService.Service.Port, instance.Spec.ConfigService.Config.ContextPath))
+	if err != nil {
+		return err
+	}
+	if configStatus.Certificate, err = utils.CertificateStatus(ctx, params.Client, instance.Namespace, naming.ConfigIngress(instance), instance.Spec.ConfigService.Ingress); err != nil {
+		return err
+	}
+	adminStatus, adminDeployment, err := utils.DeploymentStatus(ctx, params.Client, instance.Namespace, naming.AdminDeployment(instance),
+		utils.ServiceURL(naming.AdminService(instance), instance.Namespace, instance.Spec.AdminService.Service.Port, instance.Spec.AdminService.Config.ContextPath))
+	if err != nil {
+		return err
+	}
+	if adminStatus.Certificate, err = utils.CertificateStatus(ctx, params.Client, instance.Namespace, naming.AdminIngress(instance), instance.Spec.AdminService.Ingress); err != nil {
+		return err
+	}
+
+	instance.Status.ObservedGeneration = instance.Generation
+	instance.Status.ConfigService = configStatus
+	instance.Status.AdminService = adminStatus
+	utils.SetStatusConditions(&instance.Status.Conditions, instance.Generation,
+		[]*appsv1.Deployment{configDeployment, adminDeployment},
+		utils.CheckTCP(ctx, instance.Spec.ConfigDB.Host, instance.Spec.ConfigDB.Port))
+	utils.SetVersionStatus(&instance.Status.Version, schemaVersion(instance), []*appsv1.Deployment{configDeployment, adminDeployment})
+
+	if err := params.Client.Status().Patch(ctx, instance, patch); err != nil {
+		return fmt.Errorf("failed to patch status: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package apolloportal
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	"apolloconfig.com/apollo-operator/pkg/utils"
+	"apolloconfig.com/apollo-operator/pkg/utils/naming"
+	"fmt"
+)
+
+// Databases returns the PortalDB of the instance, as dumped by an ApolloBackup.
+func Databases(instance *apolloiov1alpha1.ApolloPortal) []utils.Database {
+	return []utils.Database{
+		{
+			Host:     fmt.Sprintf("%s.%s", naming.PortalDBService(instance), instance.Namespace),
+			Port:     instance.Spec.PortalDB.Service.Port,
+			Name:     instance.Spec.PortalDB.DBName,
+			Username: utils.SecretKeyRef(instance.Spec.PortalDB.UsernameSecretRef, naming.PortalDBSecret(instance), utils.SecretUsernameKey),
+			Password: utils.SecretKeyRef(instance.Spec.PortalDB.PasswordSecretRef, naming.PortalDBSecret(instance), utils.SecretPasswordKey),
+		},
+	}
+}
@@ -3,16 +3,23 @@ package apolloportal
 import (
 	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
 	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"apolloconfig.com/apollo-operator/pkg/schema"
 	"apolloconfig.com/apollo-operator/pkg/utils"
 	"apolloconfig.com/apollo-operator/pkg/utils/naming"
 	"context"
 	"fmt"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
 	"strings"
 )
 
@@ -22,7 +29,7 @@ func (o ApolloPortal) DesiredConfigMaps(ctx context.Context, instance client.Obj
 	name := naming.ConfigMap(instance)
 	labels := utils.Labels(instance, name, []string{})
 
-	data, _ := buildConfig(ctx, instance)
+	data, _ := buildConfig(ctx, instance, params)
 
 	configmap := corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -34,37 +41,43 @@ func (o ApolloPortal) DesiredConfigMaps(ctx context.Context, instance client.Obj
 		Data: data,
 	}
 
-	return []corev1.ConfigMap{configmap}
+	desired := []corev1.ConfigMap{configmap}
+	if schemaConfig := portaldbSchemaConfig(ctx, instance, params); schemaConfig != nil {
+		desired = append(desired, *schemaConfig)
+	}
+	return desired
 }
 
-func buildConfig(_ context.Context, obj client.Object) (map[string]string, error) {
+func buildConfig(ctx context.Context, obj client.Object, params models.Params) (map[string]string, error) {
 	instance := obj.(*apolloiov1alpha1.ApolloPortal)
 
 	// 从instance提取出configmap的data部分
 	data := map[string]string{}
 
+	envs, metaServers := resolveEnvironments(ctx, instance, params)
+
 	// apollo-env.properties
-	// TODO 配置meta服务的地址，比如CR中指定configservice的namespace和name
+	// NOTE 按env排序，避免map遍历顺序不同导致configmap反复更新
+	metaEnvs := make([]string, 0, len(metaServers))
+	for env := range metaServers {
+		metaEnvs = append(metaEnvs, env)
+	}
+	sort.Strings(metaEnvs)
 	var apolloEnvConfig []string
-	for env, address := range instance.Spec.Config.MetaServers {
-		apolloEnvConfig = append(apolloEnvConfig, fmt.Sprintf("%s.meta = %s", env, address))
+	for _, env := range metaEnvs {
+		apolloEnvConfig = append(apolloEnvConfig, fmt.Sprintf("%s.meta = %s", env, metaServers[env]))
 	}
 	data["apollo-env.properties"] = strings.Join(apolloEnvConfig, "\n")
 
 	// application-github.properties
-	// TODO 多种数据库支持
-	apolloGithubConfig := []string{
-		fmt.Sprintf("spring.datasource.username = %s", instance.Spec.PortalDB.Username),
-		fmt.Sprintf("spring.datasource.password = %s", instance.Spec.PortalDB.Password),
-		fmt.Sprintf("spring.datasource.url = jdbc:mysql://%s.%s:%d/%s?%s",
-			naming.PortalDBService(instance), // NOTE 一定要确保和portaldb服务名一致
-			instance.Namespace,               // NOTE 一定要确保和portaldb服务的命名空间一致
-			instance.Spec.PortalDB.Service.Port,
-			instance.Spec.PortalDB.DBName,
-			instance.Spec.PortalDB.ConnectionStringProperties),
-	}
-	if instance.Spec.Config.Envs != "" {
-		apolloGithubConfig = append(apolloGithubConfig, fmt.Sprintf("apollo.portal.envs = %s", instance.Spec.Config.Envs))
+	// NOTE 用户名和密码通过环境变量从secret中注入，不写入configmap
+	apolloGithubConfig := utils.DatasourceProperties(instance.Spec.PortalDB.Type,
+		fmt.Sprintf("%s.%s", naming.PortalDBService(instance), instance.Namespace), // NOTE 一定要确保和portaldb服务名及命名空间一致
+		instance.Spec.PortalDB.Service.Port,
+		instance.Spec.PortalDB.DBName,
+		instance.Spec.PortalDB.ConnectionStringProperties)
+	if len(envs) > 0 {
+		apolloGithubConfig = append(apolloGithubConfig, fmt.Sprintf("apollo.portal.envs = %s", strings.Join(envs, ",")))
 	}
 	if instance.Spec.Config.ContextPath != "" {
 		apolloGithubConfig = append(apolloGithubConfig, fmt.Sprintf("server.servlet.context-path = %s", instance.Spec.Config.ContextPath))
@@ -81,6 +94,96 @@ func buildConfig(_ context.Context, obj client.Object) (map[string]string, error
 
 }
 
+// resolveEnvironments returns the env names of Config.Envs followed by the names of Environments,
+// and the meta server addresses of Config.MetaServers merged with the ones resolved from the referenced ApolloEnvironments.
+func resolveEnvironments(ctx context.Context, instance *apolloiov1alpha1.ApolloPortal, params models.Params) ([]string, map[string]string) {
+	metaServers := map[string]string{}
+	for env, address := range instance.Spec.Config.MetaServers {
+		metaServers[env] = address
+	}
+
+	for _, env := range instance.Spec.Environments {
+		environment := &apolloiov1alpha1.ApolloEnvironment{}
+		nns := types.NamespacedName{Namespace: environmentNamespace(instance, env), Name: env.EnvironmentRef.Name}
+		if err := params.Client.Get(ctx, nns, environment); err != nil {
+			// NOTE 引用的环境不存在时跳过该环境，环境创建后会重新触发调谐
+			params.Log.Error(err, "failed to resolve the meta server", "env", env.Name, "apolloenvironment", nns.String())
+			continue
+		}
+		environment.Default()
+		metaServers[env.Name] = utils.ServiceURL(
+			naming.ConfigService(environment),
+			environment.Namespace,
+			environment.Spec.ConfigService.Service.Port,
+			environment.Spec.ConfigService.Config.ContextPath)
+	}
+
+	var envs []string
+	seen := map[string]bool{}
+	for _, env := range strings.Split(instance.Spec.Config.Envs, ",") {
+		env = strings.TrimSpace(env)
+		if env != "" && !seen[env] {
+			seen[env] = true
+			envs = append(envs, env)
+		}
+	}
+	for _, env := range instance.Spec.Environments {
+		if !seen[env.Name] {
+			seen[env.Name] = true
+			envs = append(envs, env.Name)
+		}
+	}
+
+	return envs, metaServers
+}
+
+// environmentNamespace returns the namespace of the ApolloEnvironment referenced by env.
+func environmentNamespace(instance client.Object, env apolloiov1alpha1.PortalEnvironment) string {
+	if env.EnvironmentRef.Namespace != "" {
+		return env.EnvironmentRef.Namespace
+	}
+	return instance.GetNamespace()
+}
+
+// DesiredSecrets 构建secret对象
+func (o ApolloPortal) DesiredSecrets(ctx context.Context, obj client.Object, params models.Params) []corev1.Secret {
+	instance := obj.(*apolloiov1alpha1.ApolloPortal)
+
+	data := map[string][]byte{}
+	if instance.Spec.PortalDB.UsernameSecretRef == nil {
+		data[utils.SecretUsernameKey] = []byte(instance.Spec.PortalDB.Username)
+	}
+	if instance.Spec.PortalDB.PasswordSecretRef == nil {
+		data[utils.SecretPasswordKey] = []byte(instance.Spec.PortalDB.Password)
+	}
+	// NOTE 用户名和密码都引用了用户的secret时，不需要operator生成secret
+	if len(data) == 0 {
+		return []corev1.Secret{}
+	}
+
+	name := naming.PortalDBSecret(instance)
+	labels := utils.Labels(instance, name, []string{})
+
+	secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.GetNamespace(),
+			Labels:    labels,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+	return []corev1.Secret{secret}
+}
+
+// datasourceEnv injects the portaldb credentials from the user provided or the operator managed secret.
+func datasourceEnv(instance *apolloiov1alpha1.ApolloPortal) []corev1.EnvVar {
+	return utils.DatasourceEnv(
+		utils.SecretKeyRef(instance.Spec.PortalDB.UsernameSecretRef, naming.PortalDBSecret(instance), utils.SecretUsernameKey),
+		utils.SecretKeyRef(instance.Spec.PortalDB.PasswordSecretRef, naming.PortalDBSecret(instance), utils.SecretPasswordKey),
+	)
+}
+
 // DesiredEndpoints 构建endpoints对象
 func (o ApolloPortal) DesiredEndpoints(ctx context.Context, instance client.Object, params models.Params) []corev1.Endpoints {
 	// TODO 目前需求只有一个subset，后续可以拓展为多个
@@ -200,12 +303,179 @@ func headlessService(ctx context.Context, instance client.Object, params models.
 	return h
 }
 
+// portaldbSchemaConfig holds the embedded PortalDB scripts mounted by the schema job.
+func portaldbSchemaConfig(_ context.Context, obj client.Object, params models.Params) *corev1.ConfigMap {
+	instance := obj.(*apolloiov1alpha1.ApolloPortal)
+	if !instance.Spec.PortalDB.Schema.Manage {
+		return nil
+	}
+
+	data, err := schema.Scripts(schema.PortalDB, schemaVersion(instance))
+	if err != nil {
+		// NOTE 错误会在WaitForSchema中写入status
+		return nil
+	}
+
+	name := naming.PortalDBSchemaConfigMap(instance)
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.GetNamespace(),
+			Labels:    utils.Labels(instance, name, []string{}),
+		},
+		Data: data,
+	}
+}
+
+// schemaVersion returns the PortalDB schema version matching the images of the instance.
+func schemaVersion(instance *apolloiov1alpha1.ApolloPortal) string {
+	return schema.ImageVersion(instance.Spec.Image)
+}
+
+// DesiredJobs 构建job对象
+func (o ApolloPortal) DesiredJobs(ctx context.Context, instance client.Object, params models.Params) []batchv1.Job {
+	desired := []batchv1.Job{}
+	type builder func(context.Context, client.Object, models.Params) *batchv1.Job
+	for _, builder := range []builder{portaldbSchemaJob} {
+		job := builder(ctx, instance, params)
+		// add only the non-nil to the list
+		if job != nil {
+			desired = append(desired, *job)
+		}
+	}
+	return desired
+}
+
+// portaldbSchemaJob creates or migrates the PortalDB schema to the version of the images.
+func portaldbSchemaJob(_ context.Context, obj client.Object, params models.Params) *batchv1.Job {
+	instance := obj.(*apolloiov1alpha1.ApolloPortal)
+	if !instance.Spec.PortalDB.Schema.Manage {
+		return nil
+	}
+
+	version := schemaVersion(instance)
+	versions, err := schema.Versions(schema.PortalDB, version)
+	if err != nil {
+		// NOTE 错误会在WaitForSchema中写入status
+		return nil
+	}
+
+	name := naming.PortalDBSchemaJob(instance)
+	var backoffLimit int32 = 6
+	var defaultMode int32 = 420
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.GetNamespace(),
+			Labels:    utils.Labels(instance, name, []string{}),
+			Annotations: map[string]string{
+				utils.SchemaVersionAnnotation: version,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyOnFailure,
+					Containers: []corev1.Container{
+						{
+							Name:    naming.Container(),
+							Image:   instance.Spec.PortalDB.Type.DefaultImage(), // NOTE 使用数据库类型的客户端
+							Command: []string{"bash", "-c", schema.Command},
+							Env: schema.Env(
+								fmt.Sprintf("%s.%s", naming.PortalDBService(instance), instance.Namespace),
+								instance.Spec.PortalDB.Service.Port,
+								instance.Spec.PortalDB.DBName,
+								version,
+								versions,
+								utils.SecretKeyRef(instance.Spec.PortalDB.UsernameSecretRef, naming.PortalDBSecret(instance), utils.SecretUsernameKey),
+								utils.SecretKeyRef(instance.Spec.PortalDB.PasswordSecretRef, naming.PortalDBSecret(instance), utils.SecretPasswordKey)),
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      naming.PortalDBSchemaConfigMap(instance),
+									MountPath: schema.MountPath,
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: naming.PortalDBSchemaConfigMap(instance),
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{Name: naming.PortalDBSchemaConfigMap(instance)}, // NOTE 和configmap的名字保持一致
+									DefaultMode:          &defaultMode,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	// NOTE schema job使用数据库镜像的客户端
+	utils.ApplyRestrictedSecurity(&job.Spec.Template.Spec, utils.DatabaseUserID)
+	return job
+}
+
+// DesiredServiceAccounts 构建serviceaccount对象，使用已有serviceaccount时不构建
+func (o ApolloPortal) DesiredServiceAccounts(ctx context.Context, obj client.Object, params models.Params) []corev1.ServiceAccount {
+	instance := obj.(*apolloiov1alpha1.ApolloPortal)
+	account := utils.ServiceAccount(instance, naming.PortalServiceAccount(instance), instance.Spec.ServiceAccount)
+	if account == nil {
+		return []corev1.ServiceAccount{}
+	}
+	return []corev1.ServiceAccount{*account}
+}
+
+// DesiredHorizontalPodAutoscalers 构建hpa对象，启用autoscaling时才构建
+func (o ApolloPortal) DesiredHorizontalPodAutoscalers(ctx context.Context, obj client.Object, params models.Params) []autoscalingv2.HorizontalPodAutoscaler {
+	instance := obj.(*apolloiov1alpha1.ApolloPortal)
+	if !instance.Spec.Autoscaling.Enabled {
+		return []autoscalingv2.HorizontalPodAutoscaler{}
+	}
+	return []autoscalingv2.HorizontalPodAutoscaler{
+		utils.HorizontalPodAutoscaler(instance, naming.PortalDeployment(instance), instance.Spec.Autoscaling),
+	}
+}
+
+// DesiredPodDisruptionBudgets 构建pdb对象，默认只在多副本时构建
+func (o ApolloPortal) DesiredPodDisruptionBudgets(ctx context.Context, obj client.Object, params models.Params) []policyv1.PodDisruptionBudget {
+	instance := obj.(*apolloiov1alpha1.ApolloPortal)
+	pdb := utils.PodDisruptionBudget(instance, naming.PortalDeployment(instance), utils.SelectorLabels(instance),
+		instance.Spec.Replicas, instance.Spec.Autoscaling, instance.Spec.PodDisruptionBudget)
+	if pdb == nil {
+		return []policyv1.PodDisruptionBudget{}
+	}
+	return []policyv1.PodDisruptionBudget{*pdb}
+}
+
+// DesiredNetworkPolicies 构建networkpolicy对象，启用networkPolicy时才构建；portaldb在集群外，不需要隔离
+func (o ApolloPortal) DesiredNetworkPolicies(ctx context.Context, obj client.Object, params models.Params) []networkingv1.NetworkPolicy {
+	instance := obj.(*apolloiov1alpha1.ApolloPortal)
+	if !instance.Spec.NetworkPolicy.Enabled {
+		return []networkingv1.NetworkPolicy{}
+	}
+	return []networkingv1.NetworkPolicy{
+		utils.NetworkPolicy(instance, naming.PortalDeployment(instance), utils.SelectorLabels(instance), instance.Spec.ContainerPort,
+			utils.PortalPeers(instance.Spec.NetworkPolicy)),
+	}
+}
+
 // DesiredDeployments 构建deployment对象
 func (o ApolloPortal) DesiredDeployments(ctx context.Context, instance client.Object, params models.Params) []appsv1.Deployment {
 	name := naming.PortalDeployment(instance)
 	labels := utils.Labels(instance, name, []string{})
 
 	spec, _ := buildDepolymentSpec(ctx, instance)
+	if !instance.(*apolloiov1alpha1.ApolloPortal).Spec.DisableConfigRollout {
+		// NOTE configmap通过subPath挂载不会热更新，内容变化时滚动更新pod
+		desired := o.DesiredConfigMaps(ctx, instance, params)
+		if err := utils.SetConfigHash(ctx, params.Client, instance.GetNamespace(), &spec.Template, desired); err != nil {
+			params.Log.Error(err, "failed to compute the config hash")
+		}
+	}
 
 	portalDepolyment := appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -259,7 +529,7 @@ func buildContainer(ctx context.Context, instance *apolloiov1alpha1.ApolloPortal
 				Protocol:      corev1.ProtocolTCP,
 			},
 		},
-		Env: append(instance.Spec.Env, corev1.EnvVar{
+		Env: append(append(datasourceEnv(instance), instance.Spec.Env...), corev1.EnvVar{
 			Name:  "SPRING_PROFILES_ACTIVE",
 			Value: instance.Spec.Config.Profiles,
 		}),
@@ -321,8 +591,11 @@ func buildDepolymentSpec(ctx context.Context, obj client.Object) (appsv1.Deploym
 			Tolerations:      instance.Spec.Tolerations,
 		},
 	}
+	utils.WritableLogs(&template)
+	utils.ApplyPodTemplateOverrides(&template, instance.Spec.PodTemplate, utils.ApolloUserID)
+	utils.ApplyServiceAccount(&template, naming.PortalServiceAccount(instance), instance.Spec.ServiceAccount)
 	return appsv1.DeploymentSpec{
-		Replicas: &instance.Spec.Replicas,
+		Replicas: utils.Replicas(instance, instance.Spec.Replicas, instance.Spec.Autoscaling),
 		Selector: &metav1.LabelSelector{MatchLabels: utils.SelectorLabels(instance)},
 		Strategy: instance.Spec.Strategy,
 		Template: template,
@@ -348,9 +621,41 @@ func buildProbe(ctx context.Context, instance *apolloiov1alpha1.ApolloPortal) (l
 	return livenessProbe, readinessProbe, nil
 }
 
+// DesiredCertificates 构建cert-manager的certificate对象，设置了tlsIssuerRef且不使用注解时才构建
+func (o ApolloPortal) DesiredCertificates(ctx context.Context, obj client.Object, params models.Params) []unstructured.Unstructured {
+	instance := obj.(*apolloiov1alpha1.ApolloPortal)
+	certificate := utils.Certificate(instance, naming.PortalIngress(instance), instance.Spec.Ingress)
+	if certificate == nil {
+		return []unstructured.Unstructured{}
+	}
+	return []unstructured.Unstructured{*certificate}
+}
+
+// DesiredHTTPRoutes 构建Gateway API的httproute对象，exposure.type为gatewayAPI时才构建
+func (o ApolloPortal) DesiredHTTPRoutes(ctx context.Context, obj client.Object, params models.Params) []unstructured.Unstructured {
+	instance := obj.(*apolloiov1alpha1.ApolloPortal)
+	route := utils.HTTPRoute(instance, naming.PortalRoute(instance), instance.Spec.Exposure, instance.Spec.Ingress,
+		instance.Spec.Config.ContextPath, naming.PortalService(instance), instance.Spec.Service.Port)
+	if route == nil {
+		return []unstructured.Unstructured{}
+	}
+	return []unstructured.Unstructured{*route}
+}
+
+// DesiredRoutes 构建OpenShift的route对象，exposure.type为route时才构建
+func (o ApolloPortal) DesiredRoutes(ctx context.Context, obj client.Object, params models.Params) []unstructured.Unstructured {
+	instance := obj.(*apolloiov1alpha1.ApolloPortal)
+	desired := []unstructured.Unstructured{}
+	return append(desired, utils.Routes(instance, naming.PortalRoute(instance), instance.Spec.Exposure, instance.Spec.Ingress,
+		naming.PortalIngress(instance), instance.Spec.Config.ContextPath, naming.PortalService(instance))...)
+}
+
 // DesiredIngresses 构建ingress对象
 func (o ApolloPortal) DesiredIngresses(ctx context.Context, obj client.Object, params models.Params) []networkingv1.Ingress {
 	instance := obj.(*apolloiov1alpha1.ApolloPortal)
+	if instance.Spec.Exposure.Type != apolloiov1alpha1.ExposureTypeIngress {
+		return []networkingv1.Ingress{}
+	}
 	name := naming.PortalIngress(instance)
 	labels := utils.Labels(instance, name, []string{})
 
@@ -360,7 +665,7 @@ func (o ApolloPortal) DesiredIngresses(ctx context.Context, obj client.Object, p
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        name,
 			Namespace:   instance.GetNamespace(),
-			Annotations: instance.Spec.Ingress.Annotations,
+			Annotations: utils.IngressAnnotations(instance.Spec.Ingress),
 			Labels:      labels,
 		},
 		Spec: spec,
@@ -376,7 +681,7 @@ func buildIngressSpec(ctx context.Context, instance *apolloiov1alpha1.ApolloPort
 	}
 
 	return networkingv1.IngressSpec{
-		TLS:              instance.Spec.Ingress.TLS,
+		TLS:              utils.IngressTLS(naming.PortalIngress(instance), instance.Spec.Ingress),
 		Rules:            rules,
 		IngressClassName: instance.Spec.Ingress.IngressClassName,
 	}, nil
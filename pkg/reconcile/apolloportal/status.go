@@ -0,0 +1,39 @@
+package apolloportal
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"apolloconfig.com/apollo-operator/pkg/utils"
+	"apolloconfig.com/apollo-operator/pkg/utils/naming"
+	"context"
+	"fmt"
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// UpdateStatus 更新status子资源
+func (o ApolloPortal) UpdateStatus(ctx context.Context, obj client.Object, params models.Params) error {
+	instance := obj.(*apolloiov1alpha1.ApolloPortal)
+	patch := client.MergeFrom(instance.DeepCopy())
+
+	portalStatus, portalDeployment, err := utils.DeploymentStatus(ctx, params.Client, instance.Namespace, naming.PortalDeployment(instance),
+		utils.ServiceURL(naming.PortalService(instance), instance.Namespace, instance.Spec.Service.Port, instance.Spec.Config.ContextPath))
+	if err != nil {
+		return err
+	}
+	if portalStatus.Certificate, err = utils.CertificateStatus(ctx, params.Client, instance.Namespace, naming.PortalIngress(instance), instance.Spec.Ingress); err != nil {
+		return err
+	}
+
+	instance.Status.ObservedGeneration = instance.Generation
+	instance.Status.Portal = portalStatus
+	utils.SetStatusConditions(&instance.Status.Conditions, instance.Generation,
+		[]*appsv1.Deployment{portalDeployment},
+		utils.CheckTCP(ctx, instance.Spec.PortalDB.Host, instance.Spec.PortalDB.Port))
+	utils.SetVersionStatus(&instance.Status.Version, schemaVersion(instance), []*appsv1.Deployment{portalDeployment})
+
+	if err := params.Client.Status().Patch(ctx, instance, patch); err != nil {
+		return fmt.Errorf("failed to patch status: %w", err)
+	}
+	return nil
+}
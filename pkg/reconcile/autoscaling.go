@@ -0,0 +1,32 @@
+package reconcile
+
+import (
+	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"context"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+
+// HorizontalPodAutoscalers reconciles the horizontalpodautoscaler(s) required for the instance in the current context.
+// The horizontalpodautoscalers of the components whose autoscaling is disabled are deleted.
+func HorizontalPodAutoscalers(ctx context.Context, instance client.Object, params models.Params) error {
+	obj, err := Lookup(instance)
+	if err != nil {
+		return err
+	}
+
+	var desired []autoscalingv2.HorizontalPodAutoscaler
+	if autoscaling, ok := obj.(AutoscalingObject); ok {
+		desired = autoscaling.DesiredHorizontalPodAutoscalers(ctx, instance, params)
+	}
+
+	r := ResourceReconciler[*autoscalingv2.HorizontalPodAutoscaler]{
+		Kind:     "horizontalpodautoscaler",
+		Desired:  pointers(desired),
+		NewList:  func() client.ObjectList { return &autoscalingv2.HorizontalPodAutoscalerList{} },
+		Selector: InstanceSelector(instance),
+	}
+	return r.Reconcile(ctx, instance, params)
+}
@@ -0,0 +1,89 @@
+package backup
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"apolloconfig.com/apollo-operator/pkg/utils/naming"
+	"context"
+	"fmt"
+	batchv1 "k8s.io/api/batch/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch
+
+// Backup creates the job dumping the databases of an ApolloBackup and records its progress in the status.
+// A finished backup is never run again.
+func Backup(ctx context.Context, obj client.Object, params models.Params) error {
+	instance := obj.(*apolloiov1alpha1.ApolloBackup)
+	if finished(instance.Status.Phase) {
+		return nil
+	}
+	patch := client.MergeFrom(instance.DeepCopy())
+
+	if err := runBackup(ctx, instance, params); err != nil {
+		instance.Status.Message = err.Error()
+		if statusErr := params.Client.Status().Patch(ctx, instance, patch); statusErr != nil {
+			params.Log.Error(statusErr, "failed to update the status")
+		}
+		return err
+	}
+
+	if err := params.Client.Status().Patch(ctx, instance, patch); err != nil {
+		return fmt.Errorf("failed to apply status changes: %w", err)
+	}
+	return nil
+}
+
+// runBackup creates the backup job if needed and sets the status from it.
+func runBackup(ctx context.Context, instance *apolloiov1alpha1.ApolloBackup, params models.Params) error {
+	_, databases, err := Target(ctx, params.Client, instance.Namespace, instance.Spec.Target)
+	if err != nil {
+		return err
+	}
+
+	claim := claimName(instance, instance.Spec.Storage)
+	instance.Status.ClaimName = claim
+	instance.Status.Path = backupDir(instance)
+	instance.Status.Databases = nil
+	for _, database := range databases {
+		instance.Status.Databases = append(instance.Status.Databases, database.Name)
+	}
+
+	job := &batchv1.Job{}
+	err = params.Client.Get(ctx, types.NamespacedName{Namespace: instance.Namespace, Name: naming.BackupJob(instance)}, job)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get job: %w", err)
+	}
+	if err == nil {
+		instance.Status.Phase, instance.Status.CompletionTime, instance.Status.Message = jobPhase(job)
+		instance.Status.StartTime = job.Status.StartTime
+		return nil
+	}
+
+	// NOTE 定时备份的job由cronjob创建，已经开始的备份的job被删除时，不再重新创建
+	if instance.Status.Phase != "" || isScheduled(instance) {
+		instance.Status.Phase = apolloiov1alpha1.BackupPhaseFailed
+		instance.Status.Message = fmt.Sprintf("job %s was deleted before the backup finished", naming.BackupJob(instance))
+		return nil
+	}
+
+	if err := expectedPVC(ctx, params, instance, claim, instance.Spec.Storage); err != nil {
+		return err
+	}
+	desired := desiredBackupJob(instance, claim, databases)
+	if err := controllerutil.SetControllerReference(instance, desired, params.Scheme); err != nil {
+		return fmt.Errorf("failed to set controller reference: %w", err)
+	}
+	if err := params.Client.Create(ctx, desired); err != nil {
+		return fmt.Errorf("failed to create: %w", err)
+	}
+	params.Log.V(2).Info("created", "job.name", desired.Name, "job.namespace", desired.Namespace)
+
+	instance.Status.Phase = apolloiov1alpha1.BackupPhasePending
+	instance.Status.Message = ""
+	return nil
+}
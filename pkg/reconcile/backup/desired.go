@@ -0,0 +1,285 @@
+package backup
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"apolloconfig.com/apollo-operator/pkg/utils"
+	"apolloconfig.com/apollo-operator/pkg/utils/naming"
+	"context"
+	"fmt"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"path"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strconv"
+)
+
+const (
+	// mountPath is where the backup and restore jobs mount the backup pvc.
+	mountPath = "/backups"
+	// volumeName is the name of the backup pvc in the pods.
+	volumeName = "backup"
+	// jobNameLabel is set on the pods of a job by the job controller.
+	jobNameLabel = "job-name"
+	// defaultStorageSize is used when the storage is omitted, the CRD default only applies inside a given storage.
+	defaultStorageSize = "5Gi"
+)
+
+// dumpScript dumps the database into ${BACKUP_DIR}/${DB_NAME}.sql.gz, and deletes the oldest backup directories
+// next to ${BACKUP_DIR} beyond ${RETENTION} when it is positive.
+const dumpScript = `set -eo pipefail
+export MYSQL_PWD="${DB_PASSWORD}"
+mkdir -p "${BACKUP_DIR}"
+mysqldump -h"${DB_HOST}" -P"${DB_PORT}" -u"${DB_USERNAME}" --single-transaction --set-gtid-purged=OFF --databases "${DB_NAME}" | gzip > "${BACKUP_DIR}/${DB_NAME}.sql.gz.tmp"
+mv "${BACKUP_DIR}/${DB_NAME}.sql.gz.tmp" "${BACKUP_DIR}/${DB_NAME}.sql.gz"
+if [ "${RETENTION}" -gt 0 ]; then
+  ls -1dt "$(dirname "${BACKUP_DIR}")"/*/ | tail -n +$((RETENTION + 1)) | xargs -r rm -rf
+fi
+`
+
+// restoreScript restores ${BACKUP_DIR}/${DB_NAME}.sql.gz, the dump drops and recreates every table.
+const restoreScript = `set -eo pipefail
+export MYSQL_PWD="${DB_PASSWORD}"
+gunzip -c "${BACKUP_DIR}/${DB_NAME}.sql.gz" | mysql -h"${DB_HOST}" -P"${DB_PORT}" -u"${DB_USERNAME}"
+`
+
+// claimName returns the pvc of storage, the pvc created by the operator is named after owner.
+func claimName(owner client.Object, storage apolloiov1alpha1.BackupStorage) string {
+	if storage.ClaimName != "" {
+		return storage.ClaimName
+	}
+	return naming.BackupPVC(owner)
+}
+
+// backupParentDir returns the directory of the backup directories of a schedule, or of the backups created by hand.
+func backupParentDir(schedule string) string {
+	if schedule != "" {
+		return path.Join("schedules", schedule)
+	}
+	return "backups"
+}
+
+// backupDir returns the directory of the dumps of a backup in the pvc.
+func backupDir(backup *apolloiov1alpha1.ApolloBackup) string {
+	return path.Join(backupParentDir(backup.Labels[utils.BackupScheduleLabel]), backup.Name)
+}
+
+// expectedPVC creates the backup pvc when it does not exist. The pvc has no owner, so that the dumps outlive the backups.
+func expectedPVC(ctx context.Context, params models.Params, owner client.Object, name string, storage apolloiov1alpha1.BackupStorage) error {
+	existing := &corev1.PersistentVolumeClaim{}
+	err := params.Client.Get(ctx, types.NamespacedName{Namespace: owner.GetNamespace(), Name: name}, existing)
+	if err == nil {
+		return nil
+	}
+	if !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get pvc: %w", err)
+	}
+
+	size := storage.Size
+	if size.IsZero() {
+		size = resource.MustParse(defaultStorageSize)
+	}
+	desired := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: owner.GetNamespace(),
+			Labels:    utils.Labels(owner, name, []string{}),
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: size,
+				},
+			},
+			StorageClassName: storage.StorageClassName,
+		},
+	}
+	if err := params.Client.Create(ctx, desired); err != nil {
+		return fmt.Errorf("failed to create pvc: %w", err)
+	}
+	params.Log.V(2).Info("created", "pvc.name", desired.Name, "pvc.namespace", desired.Namespace)
+	return nil
+}
+
+// jobPodSpec runs script in one container per database, the env is shared by the containers.
+func jobPodSpec(image, claim, script string, env []corev1.EnvVar, databases []utils.Database) corev1.PodSpec {
+	spec := corev1.PodSpec{
+		RestartPolicy: corev1.RestartPolicyOnFailure,
+		Volumes: []corev1.Volume{
+			{
+				Name: volumeName,
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim},
+				},
+			},
+		},
+	}
+	for i, database := range databases {
+		containerEnv := append([]corev1.EnvVar{}, env...)
+		containerEnv = append(containerEnv,
+			corev1.EnvVar{Name: "DB_HOST", Value: database.Host},
+			corev1.EnvVar{Name: "DB_PORT", Value: strconv.Itoa(int(database.Port))},
+			corev1.EnvVar{Name: "DB_NAME", Value: database.Name},
+			corev1.EnvVar{Name: "DB_USERNAME", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: database.Username}},
+			corev1.EnvVar{Name: "DB_PASSWORD", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: database.Password}},
+		)
+		spec.Containers = append(spec.Containers, corev1.Container{
+			Name:    fmt.Sprintf("%s-%d", naming.Container(), i), // NOTE 数据库名可能包含大写字母，不能作为容器名
+			Image:   image,
+			Command: []string{"bash", "-c", script},
+			Env:     containerEnv,
+			VolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      volumeName,
+					MountPath: mountPath,
+				},
+			},
+		})
+	}
+	// NOTE fsGroup让数据库镜像的用户可以写入备份pvc
+	utils.ApplyRestrictedSecurity(&spec, utils.DatabaseUserID)
+	return spec
+}
+
+// dumpEnv locates the dumps in the backup directory named after the job, and sets the number of backups kept in parentDir.
+func dumpEnv(parentDir string, retention int32) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{
+			// NOTE 定时备份的job名由cronjob生成，通过downward api获取
+			Name: "BACKUP_NAME",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: fmt.Sprintf("metadata.labels['%s']", jobNameLabel)},
+			},
+		},
+		{
+			Name:  "BACKUP_DIR",
+			Value: path.Join(mountPath, parentDir, "$(BACKUP_NAME)"),
+		},
+		{
+			Name:  "RETENTION",
+			Value: strconv.Itoa(int(retention)),
+		},
+	}
+}
+
+// desiredBackupJob builds the job dumping the databases of a backup created by hand.
+func desiredBackupJob(instance *apolloiov1alpha1.ApolloBackup, claim string, databases []utils.Database) *batchv1.Job {
+	name := naming.BackupJob(instance)
+	var backoffLimit int32 = 6
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.GetNamespace(),
+			Labels:    utils.Labels(instance, name, []string{}),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: databaseClientLabels()},
+				Spec:       jobPodSpec(instance.Spec.Image, claim, dumpScript, dumpEnv(backupParentDir(""), 0), databases),
+			},
+		},
+	}
+}
+
+// desiredCronJob builds the cronjob dumping the databases of a schedule, the jobs are labeled with the schedule.
+func desiredCronJob(instance *apolloiov1alpha1.ApolloBackupSchedule, claim string, databases []utils.Database) *batchv1.CronJob {
+	name := naming.BackupCronJob(instance)
+	var backoffLimit int32 = 6
+	var failedJobsHistoryLimit int32 = 1
+	suspend := instance.Spec.Suspend
+	// NOTE 超出保留数量的job由operator和ApolloBackup一起删除
+	successfulJobsHistoryLimit := instance.Spec.Retention
+
+	return &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.GetNamespace(),
+			Labels:    utils.Labels(instance, name, []string{}),
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:                   instance.Spec.Schedule,
+			Suspend:                    &suspend,
+			ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: &successfulJobsHistoryLimit,
+			FailedJobsHistoryLimit:     &failedJobsHistoryLimit,
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						utils.BackupScheduleLabel: instance.Name,
+					},
+				},
+				Spec: batchv1.JobSpec{
+					BackoffLimit: &backoffLimit,
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: databaseClientLabels()},
+						Spec: jobPodSpec(instance.Spec.Template.Image, claim, dumpScript,
+							dumpEnv(backupParentDir(instance.Name), instance.Spec.Retention), databases),
+					},
+				},
+			},
+		},
+	}
+}
+
+// desiredRestoreJob builds the job restoring the dumps of backup.
+func desiredRestoreJob(instance *apolloiov1alpha1.ApolloRestore, backup *apolloiov1alpha1.ApolloBackup, databases []utils.Database) *batchv1.Job {
+	name := naming.RestoreJob(instance)
+	var backoffLimit int32 = 2
+	env := []corev1.EnvVar{
+		{
+			Name:  "BACKUP_DIR",
+			Value: path.Join(mountPath, backup.Status.Path),
+		},
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.GetNamespace(),
+			Labels:    utils.Labels(instance, name, []string{}),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: databaseClientLabels()},
+				Spec:       jobPodSpec(backup.Spec.Image, backup.Status.ClaimName, restoreScript, env, databases),
+			},
+		},
+	}
+}
+
+// jobPhase maps the state of a job to the phase of a backup or a restore, with a message explaining a failure.
+func jobPhase(job *batchv1.Job) (apolloiov1alpha1.BackupPhase, *metav1.Time, string) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return apolloiov1alpha1.BackupPhaseCompleted, job.Status.CompletionTime, ""
+		case batchv1.JobFailed:
+			return apolloiov1alpha1.BackupPhaseFailed, &condition.LastTransitionTime, fmt.Sprintf("job %s failed: %s", job.Name, condition.Message)
+		}
+	}
+	if job.Status.StartTime != nil {
+		return apolloiov1alpha1.BackupPhaseRunning, nil, ""
+	}
+	return apolloiov1alpha1.BackupPhasePending, nil, ""
+}
+
+// finished reports whether a backup or a restore reached a final phase.
+func finished(phase apolloiov1alpha1.BackupPhase) bool {
+	return phase == apolloiov1alpha1.BackupPhaseCompleted || phase == apolloiov1alpha1.BackupPhaseFailed
+}
+
+// databaseClientLabels are the labels of the pods connecting to the databases, the NetworkPolicy of the all-in-one database accepts them.
+func databaseClientLabels() map[string]string {
+	return map[string]string{utils.DatabaseClientLabel: "true"}
+}
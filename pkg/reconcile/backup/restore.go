@@ -0,0 +1,161 @@
+package backup
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"apolloconfig.com/apollo-operator/pkg/utils"
+	"apolloconfig.com/apollo-operator/pkg/utils/naming"
+	"context"
+	"fmt"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Restore restores the dumps of a completed ApolloBackup. The target of the backup is annotated with
+// utils.RestoreAnnotation, which keeps its Deployments scaled to zero until the restore job finished.
+func Restore(ctx context.Context, obj client.Object, params models.Params) error {
+	instance := obj.(*apolloiov1alpha1.ApolloRestore)
+	if finished(instance.Status.Phase) {
+		return nil
+	}
+	patch := client.MergeFrom(instance.DeepCopy())
+
+	if err := runRestore(ctx, instance, params); err != nil {
+		instance.Status.Message = err.Error()
+		if statusErr := params.Client.Status().Patch(ctx, instance, patch); statusErr != nil {
+			params.Log.Error(statusErr, "failed to update the status")
+		}
+		return err
+	}
+
+	if err := params.Client.Status().Patch(ctx, instance, patch); err != nil {
+		return fmt.Errorf("failed to apply status changes: %w", err)
+	}
+	return nil
+}
+
+// runRestore moves the restore one step further and sets the status accordingly.
+func runRestore(ctx context.Context, instance *apolloiov1alpha1.ApolloRestore, params models.Params) error {
+	if instance.Status.Phase == "" {
+		instance.Status.Phase = apolloiov1alpha1.BackupPhasePending
+	}
+
+	backup := &apolloiov1alpha1.ApolloBackup{}
+	if err := params.Client.Get(ctx, types.NamespacedName{Namespace: instance.Namespace, Name: instance.Spec.BackupName}, backup); err != nil {
+		return fmt.Errorf("failed to get backup %s: %w", instance.Spec.BackupName, err)
+	}
+	if backup.Status.Phase != apolloiov1alpha1.BackupPhaseCompleted {
+		instance.Status.Message = fmt.Sprintf("waiting for backup %s to complete", backup.Name)
+		return nil
+	}
+
+	target, databases, err := Target(ctx, params.Client, instance.Namespace, backup.Spec.Target)
+	if err != nil {
+		return err
+	}
+
+	// 1. 停止apollo的服务
+	if err := setRestoreAnnotation(ctx, params, target, instance.Name); err != nil {
+		return err
+	}
+	scaledDown, err := scaleDown(ctx, params, target)
+	if err != nil {
+		return err
+	}
+	if !scaledDown {
+		instance.Status.Phase = apolloiov1alpha1.BackupPhaseRunning
+		instance.Status.Message = fmt.Sprintf("waiting for the deployments of %s %s to scale down", backup.Spec.Target.Kind, target.GetName())
+		return nil
+	}
+
+	// 2. 恢复数据库
+	job := &batchv1.Job{}
+	err = params.Client.Get(ctx, types.NamespacedName{Namespace: instance.Namespace, Name: naming.RestoreJob(instance)}, job)
+	if err != nil && k8serrors.IsNotFound(err) {
+		desired := desiredRestoreJob(instance, backup, databases)
+		if err := controllerutil.SetControllerReference(instance, desired, params.Scheme); err != nil {
+			return fmt.Errorf("failed to set controller reference: %w", err)
+		}
+		if err := params.Client.Create(ctx, desired); err != nil {
+			return fmt.Errorf("failed to create: %w", err)
+		}
+		params.Log.V(2).Info("created", "job.name", desired.Name, "job.namespace", desired.Namespace)
+		instance.Status.Phase = apolloiov1alpha1.BackupPhaseRunning
+		instance.Status.Message = fmt.Sprintf("restoring backup %s", backup.Name)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get job: %w", err)
+	}
+
+	phase, completionTime, message := jobPhase(job)
+	instance.Status.StartTime = job.Status.StartTime
+	if !finished(phase) {
+		instance.Status.Phase = apolloiov1alpha1.BackupPhaseRunning
+		instance.Status.Message = fmt.Sprintf("restoring backup %s", backup.Name)
+		return nil
+	}
+
+	// 3. 恢复apollo的服务
+	if err := setRestoreAnnotation(ctx, params, target, ""); err != nil {
+		return err
+	}
+	instance.Status.Phase, instance.Status.CompletionTime, instance.Status.Message = phase, completionTime, message
+	return nil
+}
+
+// setRestoreAnnotation sets the restore annotation of target to restore, or removes it when restore is empty.
+func setRestoreAnnotation(ctx context.Context, params models.Params, target client.Object, restore string) error {
+	current, ok := target.GetAnnotations()[utils.RestoreAnnotation]
+	if (restore == "" && !ok) || (restore != "" && current == restore) {
+		return nil
+	}
+
+	updated := target.DeepCopyObject().(client.Object)
+	annotations := updated.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if restore == "" {
+		delete(annotations, utils.RestoreAnnotation)
+	} else {
+		annotations[utils.RestoreAnnotation] = restore
+	}
+	updated.SetAnnotations(annotations)
+
+	if err := params.Client.Patch(ctx, updated, client.MergeFrom(target)); err != nil {
+		return fmt.Errorf("failed to annotate %s: %w", target.GetName(), err)
+	}
+	return nil
+}
+
+// scaleDown scales the apollo Deployments of target to zero and reports whether all their pods are gone.
+// The reconciler of target keeps them at zero while the restore annotation is set.
+func scaleDown(ctx context.Context, params models.Params, target client.Object) (bool, error) {
+	scaledDown := true
+	for _, name := range []string{naming.ConfigDeployment(target), naming.AdminDeployment(target), naming.PortalDeployment(target)} {
+		deployment := &appsv1.Deployment{}
+		if err := params.Client.Get(ctx, types.NamespacedName{Namespace: target.GetNamespace(), Name: name}, deployment); err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return false, fmt.Errorf("failed to get deployment %s: %w", name, err)
+		}
+
+		if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas != 0 {
+			updated := deployment.DeepCopy()
+			updated.Spec.Replicas = new(int32)
+			if err := params.Client.Patch(ctx, updated, client.MergeFrom(deployment)); err != nil {
+				return false, fmt.Errorf("failed to scale down deployment %s: %w", name, err)
+			}
+			params.Log.V(2).Info("scaled down", "deployment.name", name, "deployment.namespace", target.GetNamespace())
+		}
+		if deployment.Status.Replicas > 0 {
+			scaledDown = false
+		}
+	}
+	return scaledDown, nil
+}
@@ -0,0 +1,164 @@
+package backup
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"apolloconfig.com/apollo-operator/pkg/utils"
+	"context"
+	"fmt"
+	batchv1 "k8s.io/api/batch/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sort"
+)
+
+// +kubebuilder:rbac:groups="batch",resources=cronjobs,verbs=get;list;watch;create;update;patch;delete
+
+// Schedule keeps the cronjob of an ApolloBackupSchedule up to date, records an ApolloBackup for every job started by
+// the cronjob and deletes the backups beyond the retention.
+func Schedule(ctx context.Context, obj client.Object, params models.Params) error {
+	instance := obj.(*apolloiov1alpha1.ApolloBackupSchedule)
+	patch := client.MergeFrom(instance.DeepCopy())
+
+	_, databases, err := Target(ctx, params.Client, instance.Namespace, instance.Spec.Template.Target)
+	if err != nil {
+		return err
+	}
+
+	// NOTE 定时创建的ApolloBackup使用同一个pvc
+	template := instance.Spec.Template.DeepCopy()
+	template.Storage.ClaimName = claimName(instance, template.Storage)
+	if err := expectedPVC(ctx, params, instance, template.Storage.ClaimName, template.Storage); err != nil {
+		return err
+	}
+
+	cronJob, err := expectedCronJob(ctx, params, instance, desiredCronJob(instance, template.Storage.ClaimName, databases))
+	if err != nil {
+		return err
+	}
+	instance.Status.LastScheduleTime = cronJob.Status.LastScheduleTime
+
+	if err := recordBackups(ctx, params, instance, *template); err != nil {
+		return err
+	}
+	if err := pruneBackups(ctx, params, instance); err != nil {
+		return err
+	}
+
+	if err := params.Client.Status().Patch(ctx, instance, patch); err != nil {
+		return fmt.Errorf("failed to apply status changes: %w", err)
+	}
+	return nil
+}
+
+// expectedCronJob server-side applies the cronjob of the schedule and returns the applied one.
+func expectedCronJob(ctx context.Context, params models.Params, instance *apolloiov1alpha1.ApolloBackupSchedule, desired *batchv1.CronJob) (*batchv1.CronJob, error) {
+	if err := controllerutil.SetControllerReference(instance, desired, params.Scheme); err != nil {
+		return nil, fmt.Errorf("failed to set controller reference: %w", err)
+	}
+
+	if err := utils.Apply(ctx, params.Client, params.Scheme, desired); err != nil {
+		return nil, fmt.Errorf("failed to apply changes: %w", err)
+	}
+	params.Log.V(2).Info("applied", "cronjob.name", desired.Name, "cronjob.namespace", desired.Namespace)
+	return desired, nil
+}
+
+// recordBackups creates an ApolloBackup named after every job of the schedule, the ApolloBackup reports the state of the job.
+func recordBackups(ctx context.Context, params models.Params, instance *apolloiov1alpha1.ApolloBackupSchedule, template apolloiov1alpha1.ApolloBackupSpec) error {
+	jobs := &batchv1.JobList{}
+	if err := params.Client.List(ctx, jobs, client.InNamespace(instance.Namespace),
+		client.MatchingLabels{utils.BackupScheduleLabel: instance.Name}); err != nil {
+		return fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	for _, job := range jobs.Items {
+		backup := &apolloiov1alpha1.ApolloBackup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      job.Name,
+				Namespace: instance.Namespace,
+				Labels: map[string]string{
+					utils.BackupScheduleLabel: instance.Name,
+				},
+			},
+			Spec: template,
+		}
+		if err := controllerutil.SetControllerReference(instance, backup, params.Scheme); err != nil {
+			return fmt.Errorf("failed to set controller reference: %w", err)
+		}
+		if err := params.Client.Create(ctx, backup); err != nil {
+			if k8serrors.IsAlreadyExists(err) {
+				continue
+			}
+			return fmt.Errorf("failed to create backup %s: %w", backup.Name, err)
+		}
+		params.Log.V(2).Info("created", "apollobackup.name", backup.Name, "apollobackup.namespace", backup.Namespace)
+	}
+	return nil
+}
+
+// pruneBackups deletes the ApolloBackups of the schedule beyond the retention together with their jobs,
+// and records the retained ones in the status. The dumps are deleted by the backup job.
+func pruneBackups(ctx context.Context, params models.Params, instance *apolloiov1alpha1.ApolloBackupSchedule) error {
+	backups := &apolloiov1alpha1.ApolloBackupList{}
+	if err := params.Client.List(ctx, backups, client.InNamespace(instance.Namespace),
+		client.MatchingLabels{utils.BackupScheduleLabel: instance.Name}); err != nil {
+		return fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	items := backups.Items
+	// newest first, the job names of a cronjob grow with the schedule time
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].CreationTimestamp.Equal(&items[j].CreationTimestamp) {
+			return items[j].CreationTimestamp.Before(&items[i].CreationTimestamp)
+		}
+		return items[i].Name > items[j].Name
+	})
+
+	instance.Status.Backups = nil
+	instance.Status.LastBackup = ""
+	instance.Status.LastSuccessfulBackup = ""
+	for i := range items {
+		backup := &items[i]
+		if int32(i) >= instance.Spec.Retention {
+			if err := deleteBackup(ctx, params, backup); err != nil {
+				return err
+			}
+			continue
+		}
+
+		instance.Status.Backups = append(instance.Status.Backups, backup.Name)
+		if instance.Status.LastBackup == "" {
+			instance.Status.LastBackup = backup.Name
+		}
+		if instance.Status.LastSuccessfulBackup == "" && backup.Status.Phase == apolloiov1alpha1.BackupPhaseCompleted {
+			instance.Status.LastSuccessfulBackup = backup.Name
+		}
+	}
+	return nil
+}
+
+// deleteBackup deletes a scheduled ApolloBackup and its job, so that the job is not recorded again.
+func deleteBackup(ctx context.Context, params models.Params, backup *apolloiov1alpha1.ApolloBackup) error {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      backup.Name,
+			Namespace: backup.Namespace,
+		},
+	}
+	if err := params.Client.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete job %s: %w", job.Name, err)
+	}
+	if err := params.Client.Delete(ctx, backup); err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete backup %s: %w", backup.Name, err)
+	}
+	params.Log.V(2).Info("deleted", "apollobackup.name", backup.Name, "apollobackup.namespace", backup.Namespace)
+	return nil
+}
+
+// isScheduled reports whether the backup was created by an ApolloBackupSchedule.
+func isScheduled(backup *apolloiov1alpha1.ApolloBackup) bool {
+	return backup.Labels[utils.BackupScheduleLabel] != ""
+}
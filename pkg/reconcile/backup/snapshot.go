@@ -0,0 +1,51 @@
+package backup
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"apolloconfig.com/apollo-operator/pkg/utils/naming"
+	"context"
+	"fmt"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=apolloconfig.com,resources=apollobackups,verbs=get;list;watch;create
+
+// Snapshot backs up the databases of an instance being deleted with an ApolloBackup and reports whether it completed.
+// The ApolloBackup has no owner, so that it outlives the instance and can be restored into a new one.
+func Snapshot(ctx context.Context, params models.Params, kind string, instance client.Object) (bool, error) {
+	backup := &apolloiov1alpha1.ApolloBackup{}
+	nns := types.NamespacedName{Namespace: instance.GetNamespace(), Name: naming.SnapshotBackup(instance)}
+	err := params.Client.Get(ctx, nns, backup)
+	if err != nil && k8serrors.IsNotFound(err) {
+		backup = &apolloiov1alpha1.ApolloBackup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      nns.Name,
+				Namespace: nns.Namespace,
+			},
+			Spec: apolloiov1alpha1.ApolloBackupSpec{
+				Target: apolloiov1alpha1.BackupTarget{Kind: kind, Name: instance.GetName()},
+			},
+		}
+		if err := params.Client.Create(ctx, backup); err != nil {
+			return false, fmt.Errorf("failed to create the snapshot: %w", err)
+		}
+		params.Log.V(2).Info("created", "apollobackup.name", backup.Name, "apollobackup.namespace", backup.Namespace)
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to get the snapshot: %w", err)
+	}
+
+	switch backup.Status.Phase {
+	case apolloiov1alpha1.BackupPhaseCompleted:
+		return true, nil
+	case apolloiov1alpha1.BackupPhaseFailed:
+		// NOTE 快照失败时不删除数据，修改deletionPolicy后才能继续删除
+		return false, fmt.Errorf("the snapshot %s failed, change the deletionPolicy to delete %s %s without it: %s",
+			backup.Name, kind, instance.GetName(), backup.Status.Message)
+	}
+	return false, nil
+}
@@ -0,0 +1,58 @@
+// Package backup reconciles the ApolloBackup, ApolloBackupSchedule and ApolloRestore kinds, which dump the databases
+// of an apollo resource with mysqldump into a PVC and restore them.
+package backup
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	"apolloconfig.com/apollo-operator/pkg/reconcile/apollo"
+	"apolloconfig.com/apollo-operator/pkg/reconcile/apolloenvironment"
+	"apolloconfig.com/apollo-operator/pkg/reconcile/apolloportal"
+	"apolloconfig.com/apollo-operator/pkg/utils"
+	"context"
+	"fmt"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Target fetches the apollo resource referenced by target and returns the databases it uses.
+// Only mysql databases are supported, they are dumped with mysqldump.
+func Target(ctx context.Context, c client.Client, namespace string, target apolloiov1alpha1.BackupTarget) (client.Object, []utils.Database, error) {
+	nns := types.NamespacedName{Namespace: namespace, Name: target.Name}
+
+	var (
+		instance  client.Object
+		dbType    apolloiov1alpha1.DatabaseType
+		databases []utils.Database
+	)
+	switch target.Kind {
+	case "Apollo":
+		allInOne := &apolloiov1alpha1.Apollo{}
+		if err := c.Get(ctx, nns, allInOne); err != nil {
+			return nil, nil, fmt.Errorf("failed to get %s %s: %w", target.Kind, target.Name, err)
+		}
+		// NOTE 未启用webhook时，在内存中补齐默认值
+		allInOne.Default()
+		instance, dbType, databases = allInOne, allInOne.Spec.Database.Type, apollo.Databases(allInOne)
+	case "ApolloEnvironment":
+		environment := &apolloiov1alpha1.ApolloEnvironment{}
+		if err := c.Get(ctx, nns, environment); err != nil {
+			return nil, nil, fmt.Errorf("failed to get %s %s: %w", target.Kind, target.Name, err)
+		}
+		environment.Default()
+		instance, dbType, databases = environment, environment.Spec.ConfigDB.Type, apolloenvironment.Databases(environment)
+	case "ApolloPortal":
+		portal := &apolloiov1alpha1.ApolloPortal{}
+		if err := c.Get(ctx, nns, portal); err != nil {
+			return nil, nil, fmt.Errorf("failed to get %s %s: %w", target.Kind, target.Name, err)
+		}
+		portal.Default()
+		instance, dbType, databases = portal, portal.Spec.PortalDB.Type, apolloportal.Databases(portal)
+	default:
+		return nil, nil, fmt.Errorf("unsupported target kind %q", target.Kind)
+	}
+
+	if dbType == apolloiov1alpha1.DatabaseTypePostgreSQL {
+		return nil, nil, fmt.Errorf("%s %s uses postgresql, only mysql databases can be backed up", target.Kind, target.Name)
+	}
+	return instance, databases, nil
+}
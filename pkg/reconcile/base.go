@@ -1,65 +1,108 @@
 package reconcile
 
 import (
-	"apolloconfig.com/apollo-operator/pkg/reconcile/apollo"
-	"apolloconfig.com/apollo-operator/pkg/reconcile/apolloenvironment"
-	"apolloconfig.com/apollo-operator/pkg/reconcile/apolloportal"
 	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
 	"context"
+	"fmt"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"reflect"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sync"
 )
 
+// ApolloObject builds the objects of an Apollo kind, they are created, updated and pruned by a ResourceReconciler.
 type ApolloObject interface {
-	// configmap
-	DesiredConfigMaps(ctx context.Context, instance client.Object, params models.Params) []corev1.ConfigMap                              // 构建configmap对象
-	ExpectedConfigMaps(ctx context.Context, instance client.Object, params models.Params, expected []corev1.ConfigMap, retry bool) error // 创建或更新configmap
-	DeleteConfigMaps(ctx context.Context, instance client.Object, params models.Params, expected []corev1.ConfigMap) error               // 删除configmap
-
-	// endpoints
-	DesiredEndpoints(ctx context.Context, instance client.Object, params models.Params) []corev1.Endpoints                              // 构建endpoints对象
-	ExpectedEndpoints(ctx context.Context, instance client.Object, params models.Params, expected []corev1.Endpoints, retry bool) error // 创建或更新endpoints
-	DeleteEndpoints(ctx context.Context, instance client.Object, params models.Params, expected []corev1.Endpoints) error               // 删除endpoints
-
-	// service
-	DesiredServices(ctx context.Context, instance client.Object, params models.Params) []corev1.Service                  // 构建service对象
-	ExpectedServices(ctx context.Context, instance client.Object, params models.Params, expected []corev1.Service) error // 创建或更新service
-	DeleteServices(ctx context.Context, instance client.Object, params models.Params, expected []corev1.Service) error   // 删除service
-
-	// deployment
-	DesiredDeployments(ctx context.Context, instance client.Object, params models.Params) []appsv1.Deployment                  // 构建deployment对象
-	ExpectedDeployments(ctx context.Context, instance client.Object, params models.Params, expected []appsv1.Deployment) error // 创建或更新deployment
-	DeleteDeployments(ctx context.Context, instance client.Object, params models.Params, expected []appsv1.Deployment) error   // 删除deployment
-
-	// ingerss
-	DesiredIngresses(ctx context.Context, instance client.Object, params models.Params) []networkingv1.Ingress                  // 构建ingress对象
-	ExpectedIngresses(ctx context.Context, instance client.Object, params models.Params, expected []networkingv1.Ingress) error // 创建或更新ingress
-	DeleteIngresses(ctx context.Context, instance client.Object, params models.Params, expected []networkingv1.Ingress) error   // 删除ingress
+	DesiredConfigMaps(ctx context.Context, instance client.Object, params models.Params) []corev1.ConfigMap    // 构建configmap对象
+	DesiredSecrets(ctx context.Context, instance client.Object, params models.Params) []corev1.Secret          // 构建secret对象
+	DesiredEndpoints(ctx context.Context, instance client.Object, params models.Params) []corev1.Endpoints     // 构建endpoints对象
+	DesiredServices(ctx context.Context, instance client.Object, params models.Params) []corev1.Service        // 构建service对象
+	DesiredDeployments(ctx context.Context, instance client.Object, params models.Params) []appsv1.Deployment  // 构建deployment对象
+	DesiredIngresses(ctx context.Context, instance client.Object, params models.Params) []networkingv1.Ingress // 构建ingress对象
+	DesiredJobs(ctx context.Context, instance client.Object, params models.Params) []batchv1.Job               // 构建job对象
 
+	// status
+	UpdateStatus(ctx context.Context, instance client.Object, params models.Params) error // 更新status子资源
 }
 
-var (
-	apolloPortal      apolloportal.ApolloPortal
-	apolloEnvironment apolloenvironment.ApolloEnvironment
-	apolloAllInOne    apollo.ApolloAllInOne
-)
+// StatefulSetObject is implemented by the kinds running a statefulset, e.g. the database of the all-in-one kind.
+type StatefulSetObject interface {
+	DesiredStatefulSets(ctx context.Context, instance client.Object, params models.Params) []appsv1.StatefulSet // 构建statefulset对象
+}
+
+// ServiceAccountObject is implemented by the kinds running their pods with their own serviceaccounts.
+type ServiceAccountObject interface {
+	DesiredServiceAccounts(ctx context.Context, instance client.Object, params models.Params) []corev1.ServiceAccount // 构建serviceaccount对象
+}
+
+// AutoscalingObject is implemented by the kinds whose deployments can be scaled by a HorizontalPodAutoscaler.
+type AutoscalingObject interface {
+	DesiredHorizontalPodAutoscalers(ctx context.Context, instance client.Object, params models.Params) []autoscalingv2.HorizontalPodAutoscaler // 构建hpa对象
+}
+
+// DisruptionBudgetObject is implemented by the kinds protecting the pods of their deployments with poddisruptionbudgets.
+type DisruptionBudgetObject interface {
+	DesiredPodDisruptionBudgets(ctx context.Context, instance client.Object, params models.Params) []policyv1.PodDisruptionBudget // 构建pdb对象
+}
+
+// NetworkPolicyObject is implemented by the kinds isolating their pods with networkpolicies.
+type NetworkPolicyObject interface {
+	DesiredNetworkPolicies(ctx context.Context, instance client.Object, params models.Params) []networkingv1.NetworkPolicy // 构建networkpolicy对象
+}
+
+// CertificateObject is implemented by the kinds requesting the certificates of their ingresses from cert-manager.
+type CertificateObject interface {
+	DesiredCertificates(ctx context.Context, instance client.Object, params models.Params) []unstructured.Unstructured // 构建certificate对象
+}
+
+// HTTPRouteObject is implemented by the kinds exposing their components through the Gateway API.
+type HTTPRouteObject interface {
+	DesiredHTTPRoutes(ctx context.Context, instance client.Object, params models.Params) []unstructured.Unstructured // 构建httproute对象
+}
 
-func init() {
-	apolloPortal = apolloportal.NewApolloPortal()
-	apolloEnvironment = apolloenvironment.NewApolloEnvironment()
-	apolloAllInOne = apollo.NewApolloAllInOne()
+// RouteObject is implemented by the kinds exposing their components through OpenShift Routes.
+type RouteObject interface {
+	DesiredRoutes(ctx context.Context, instance client.Object, params models.Params) []unstructured.Unstructured // 构建route对象
 }
 
-func ApolloPortal() apolloportal.ApolloPortal {
-	return apolloPortal
+// SchemaObject is implemented by the kinds managing a database schema, the deployments wait for it.
+type SchemaObject interface {
+	WaitForSchema(ctx context.Context, instance client.Object, params models.Params) error
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[reflect.Type]ApolloObject{}
+)
+
+// Register maps the Go type of obj to the ApolloObject building its objects, the controllers register their kind at setup.
+// Kinds embedding the operator can register their own types the same way, registering a type again replaces its handler.
+func Register(obj client.Object, handler ApolloObject) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[reflect.TypeOf(obj)] = handler
 }
 
-func ApolloEnvironment() apolloenvironment.ApolloEnvironment {
-	return apolloEnvironment
+// Lookup returns the ApolloObject registered for the Go type of instance.
+func Lookup(instance client.Object) (ApolloObject, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	handler, ok := registry[reflect.TypeOf(instance)]
+	if !ok {
+		return nil, fmt.Errorf("no handler is registered for %T", instance)
+	}
+	return handler, nil
 }
 
-func ApolloAllInOne() apollo.ApolloAllInOne {
-	return apolloAllInOne
+// kindOf returns the kind of instance, the GVK is empty for objects read by a typed client.
+func kindOf(instance client.Object) string {
+	if kind := instance.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+		return kind
+	}
+	return reflect.TypeOf(instance).Elem().Name()
 }
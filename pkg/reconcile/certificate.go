@@ -0,0 +1,28 @@
+package reconcile
+
+import (
+	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"apolloconfig.com/apollo-operator/pkg/utils"
+	"context"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
+
+// Certificates reconciles the cert-manager certificate(s) of the ingresses required for the instance in the current context.
+// Nothing is done when cert-manager is not installed and no certificate is requested.
+func Certificates(ctx context.Context, instance client.Object, params models.Params) error {
+	obj, err := Lookup(instance)
+	if err != nil {
+		return err
+	}
+
+	var desired []unstructured.Unstructured
+	if certificate, ok := obj.(CertificateObject); ok {
+		desired = certificate.DesiredCertificates(ctx, instance, params)
+	}
+
+	return optionalResources(ctx, instance, params, "certificate", utils.CertificateGVK, desired,
+		"cert-manager is not installed, the certificates cannot be requested")
+}
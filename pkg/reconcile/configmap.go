@@ -4,6 +4,8 @@ import (
 	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
 	"context"
 	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	"reflect"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -13,32 +15,24 @@ import (
 // ConfigMaps reconciles the configmap(s) required for the instance in the current context.
 func ConfigMaps(ctx context.Context, instance client.Object, params models.Params) error {
 
-	var obj ApolloObject
-	kind := instance.GetObjectKind().GroupVersionKind().Kind
-	switch kind {
-	case "ApolloPortal":
-		obj = ApolloPortal()
-	case "ApolloEnvironment":
-		obj = ApolloEnvironment()
-	case "Apollo":
-		obj = ApolloAllInOne()
+	obj, err := Lookup(instance)
+	if err != nil {
+		return err
 	}
 
 	desired := obj.DesiredConfigMaps(ctx, instance, params)
 
-	// 可以把资源全抽象为接口，直接放reconcile下，reconcile下文件中策略模式，选择不同的apollo对象，文件夹下的文件可以按照 构建对象、创建更新、删除 的模式
-
-	// TODO 可以优化为先获取create、upodate、delete列表，然后再统一apply
-
-	// first, handle the create/update parts
-	if err := obj.ExpectedConfigMaps(ctx, instance, params, desired, true); err != nil {
-		return fmt.Errorf("failed to reconcile the expected configmaps: %w", err)
+	r := ResourceReconciler[*corev1.ConfigMap]{
+		Kind:     "configmap",
+		Desired:  pointers(desired),
+		NewList:  func() client.ObjectList { return &corev1.ConfigMapList{} },
+		Selector: InstanceSelector(instance),
+		Mutate: func(desired, existing *corev1.ConfigMap) error {
+			if !reflect.DeepEqual(desired.Data, existing.Data) {
+				params.Recorder.Event(existing, "Normal", "ConfigUpdate ", fmt.Sprintf("%s Config changed - %s/%s", kindOf(instance), desired.Namespace, desired.Name))
+			}
+			return nil
+		},
 	}
-
-	// then, delete the extra objects
-	if err := obj.DeleteConfigMaps(ctx, instance, params, desired); err != nil {
-		return fmt.Errorf("failed to reconcile the configmaps to be deleted: %w", err)
-	}
-
-	return nil
+	return r.Reconcile(ctx, instance, params)
 }
@@ -2,8 +2,10 @@ package reconcile
 
 import (
 	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"apolloconfig.com/apollo-operator/pkg/utils"
 	"context"
-	"fmt"
+	appsv1 "k8s.io/api/apps/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -11,28 +13,58 @@ import (
 
 // Deployments reconciles the deployment(s) required for the instance in the current context.
 func Deployments(ctx context.Context, instance client.Object, params models.Params) error {
-	var obj ApolloObject
-
-	// TODO switch 修改一下
-	kind := instance.GetObjectKind().GroupVersionKind().Kind
-	switch kind {
-	case "ApolloPortal":
-		obj = ApolloPortal()
-	case "ApolloEnvironment":
-		obj = ApolloEnvironment()
-	case "Apollo":
-		obj = ApolloAllInOne()
+	obj, err := Lookup(instance)
+	if err != nil {
+		return err
 	}
 	desired := obj.DesiredDeployments(ctx, instance, params)
 
-	if err := obj.ExpectedDeployments(ctx, instance, params, desired); err != nil {
-		return fmt.Errorf("failed to reconcile the expected deployments: %w", err)
+	// NOTE deployment按顺序升级，镜像变化时要等前面的deployment全部可用；本次调谐中新建或删除的deployment视为未就绪
+	var order []string
+	for _, deployment := range desired {
+		order = append(order, deployment.Name)
 	}
+	reconciled := map[string]*appsv1.Deployment{}
+	r := ResourceReconciler[*appsv1.Deployment]{
+		Kind:     "deployment",
+		Desired:  pointers(desired),
+		NewList:  func() client.ObjectList { return &appsv1.DeploymentList{} },
+		Selector: InstanceSelector(instance),
+		Replace: func(desired, existing *appsv1.Deployment) (bool, []client.DeleteOption, error) {
+			// Selector is an immutable field, if set, we cannot modify it otherwise we will have reconciliation error.
+			return !apiequality.Semantic.DeepEqual(desired.Spec.Selector, existing.Spec.Selector), nil, nil
+		},
+		Mutate: func(desired, existing *appsv1.Deployment) error {
+			// An upgrade is paused while a previous deployment is rolling out or failed to roll out, the Degraded condition reports the failure.
+			if utils.ImagesChanged(desired, existing) {
+				if pending := rolloutPending(desired.Name, order, reconciled); pending != "" {
+					params.Log.V(2).Info("waiting for the previous deployment to be available before upgrading", "deployment.name", desired.Name, "deployment.namespace", desired.Namespace, "waiting.for", pending)
+					reconciled[desired.Name] = existing
+					return ErrSkip
+				}
+			}
 
-	// then, delete the extra objects
-	if err := obj.DeleteDeployments(ctx, instance, params, desired); err != nil {
-		return fmt.Errorf("failed to reconcile the deployments to be deleted: %w", err)
+			// NOTE desired在apply之后保存apiserver返回的最新状态
+			reconciled[desired.Name] = desired
+			return nil
+		},
 	}
+	return r.Reconcile(ctx, instance, params)
+}
 
-	return nil
+// rolloutPending returns the name of the first deployment ordered before name which is not available yet.
+func rolloutPending(name string, order []string, reconciled map[string]*appsv1.Deployment) string {
+	var previous []*appsv1.Deployment
+	for _, before := range order {
+		if before == name {
+			break
+		}
+		if current, ok := reconciled[before]; ok {
+			previous = append(previous, current)
+			continue
+		}
+		// created or deleted in this reconcile cycle
+		return before
+	}
+	return utils.RolloutPending(previous)
 }
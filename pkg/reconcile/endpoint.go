@@ -4,6 +4,8 @@ import (
 	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
 	"context"
 	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -12,31 +14,24 @@ import (
 // Endpoints reconciles the endpoint(s) required for the instance in the current context.
 func Endpoints(ctx context.Context, instance client.Object, params models.Params) error {
 
-	var obj ApolloObject
-
-	kind := instance.GetObjectKind().GroupVersionKind().Kind
-	switch kind {
-	case "ApolloPortal":
-		obj = ApolloPortal()
-	case "ApolloEnvironment":
-		obj = ApolloEnvironment()
-	case "Apollo":
-		obj = ApolloAllInOne()
+	obj, err := Lookup(instance)
+	if err != nil {
+		return err
 	}
 
 	desired := obj.DesiredEndpoints(ctx, instance, params)
 
-	// TODO 可以优化为先获取create、upodate、delete列表，然后再统一apply
-
-	// first, handle the create/update parts
-	if err := obj.ExpectedEndpoints(ctx, instance, params, desired, true); err != nil {
-		return fmt.Errorf("failed to reconcile the expected Endpoints: %w", err)
+	r := ResourceReconciler[*corev1.Endpoints]{
+		Kind:     "endpoints",
+		Desired:  pointers(desired),
+		NewList:  func() client.ObjectList { return &corev1.EndpointsList{} },
+		Selector: InstanceSelector(instance),
+		Mutate: func(desired, existing *corev1.Endpoints) error {
+			if !apiequality.Semantic.DeepEqual(desired.Subsets, existing.Subsets) {
+				params.Recorder.Event(existing, "Normal", "Endpoints Update ", fmt.Sprintf("%s Endpoints changed - %s/%s", kindOf(instance), desired.Namespace, desired.Name))
+			}
+			return nil
+		},
 	}
-
-	// then, delete the extra objects
-	if err := obj.DeleteEndpoints(ctx, instance, params, desired); err != nil {
-		return fmt.Errorf("failed to reconcile the Endpoints to be deleted: %w", err)
-	}
-
-	return nil
+	return r.Reconcile(ctx, instance, params)
 }
@@ -0,0 +1,70 @@
+package reconcile
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	"apolloconfig.com/apollo-operator/pkg/reconcile/backup"
+	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"apolloconfig.com/apollo-operator/pkg/utils"
+	"context"
+	"fmt"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// FinalizerObject is implemented by the kinds keeping data or creating resources which cannot be owned by the instance,
+// e.g. cluster scoped resources, resources in other namespaces or rows in an external database.
+type FinalizerObject interface {
+	// Cleanup deletes or retains them according to policy when the instance is deleted, it returns false while
+	// the cleanup is in progress.
+	Cleanup(ctx context.Context, instance client.Object, params models.Params, policy apolloiov1alpha1.DeletionPolicy) (bool, error)
+}
+
+// AddFinalizer adds the finalizer of the operator to instance, so that it is cleaned up before it is deleted.
+func AddFinalizer(ctx context.Context, instance client.Object, params models.Params) error {
+	if controllerutil.ContainsFinalizer(instance, utils.Finalizer) {
+		return nil
+	}
+	patch := client.MergeFromWithOptions(instance.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
+	controllerutil.AddFinalizer(instance, utils.Finalizer)
+	if err := params.Client.Patch(ctx, instance, patch); err != nil {
+		return fmt.Errorf("failed to add the finalizer: %w", err)
+	}
+	return nil
+}
+
+// Finalize cleans up an instance being deleted according to policy and removes the finalizer once the cleanup is done,
+// it returns false while the cleanup is in progress. Snapshot backs up the databases before the cleanup hook of the kind runs.
+func Finalize(ctx context.Context, instance client.Object, params models.Params, policy apolloiov1alpha1.DeletionPolicy) (bool, error) {
+	if !controllerutil.ContainsFinalizer(instance, utils.Finalizer) {
+		return true, nil
+	}
+
+	if policy == apolloiov1alpha1.DeletionPolicySnapshot {
+		done, err := backup.Snapshot(ctx, params, kindOf(instance), instance)
+		if err != nil || !done {
+			return false, err
+		}
+	}
+
+	handler, err := Lookup(instance)
+	if err != nil {
+		return false, err
+	}
+	if obj, ok := handler.(FinalizerObject); ok {
+		done, err := obj.Cleanup(ctx, instance, params, policy)
+		if err != nil {
+			return false, fmt.Errorf("failed to clean up: %w", err)
+		}
+		if !done {
+			return false, nil
+		}
+	}
+
+	patch := client.MergeFromWithOptions(instance.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
+	controllerutil.RemoveFinalizer(instance, utils.Finalizer)
+	if err := params.Client.Patch(ctx, instance, patch); err != nil {
+		return false, fmt.Errorf("failed to remove the finalizer: %w", err)
+	}
+	params.Log.V(2).Info("finalized", "deletionPolicy", policy)
+	return true, nil
+}
@@ -0,0 +1,28 @@
+package reconcile
+
+import (
+	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"apolloconfig.com/apollo-operator/pkg/utils"
+	"context"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;create;update;patch;delete
+
+// HTTPRoutes reconciles the Gateway API httproute(s) required for the instance in the current context.
+// Nothing is done when the Gateway API is not installed and no component is exposed through it.
+func HTTPRoutes(ctx context.Context, instance client.Object, params models.Params) error {
+	obj, err := Lookup(instance)
+	if err != nil {
+		return err
+	}
+
+	var desired []unstructured.Unstructured
+	if route, ok := obj.(HTTPRouteObject); ok {
+		desired = route.DesiredHTTPRoutes(ctx, instance, params)
+	}
+
+	return optionalResources(ctx, instance, params, "httproute", utils.HTTPRouteGVK, desired,
+		"the Gateway API is not installed, the httproutes cannot be created")
+}
@@ -0,0 +1,42 @@
+package reconcile
+
+import (
+	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"apolloconfig.com/apollo-operator/pkg/utils"
+	"context"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups="batch",resources=jobs,verbs=get;list;watch;create;update;patch;delete
+
+// Job reconciles the job(s) required for the instance in the current context.
+func Job(ctx context.Context, instance client.Object, params models.Params) error {
+	obj, err := Lookup(instance)
+	if err != nil {
+		return err
+	}
+
+	desired := obj.DesiredJobs(ctx, instance, params)
+
+	// NOTE job默认orphan自己的pod，删除时需要指定Background
+	deleteOptions := []client.DeleteOption{client.PropagationPolicy(metav1.DeletePropagationBackground)}
+
+	// NOTE job的pod模板不可修改，只会创建，不会更新
+	r := ResourceReconciler[*batchv1.Job]{
+		Kind:       "job",
+		Desired:    pointers(desired),
+		NewList:    func() client.ObjectList { return &batchv1.JobList{} },
+		Selector:   InstanceSelector(instance),
+		CreateOnly: true,
+		Replace: func(desired, existing *batchv1.Job) (bool, []client.DeleteOption, error) {
+			// A job started for another schema version or password rotation is deleted and created again in the next reconcile cycle.
+			replace := desired.Annotations[utils.SchemaVersionAnnotation] != existing.Annotations[utils.SchemaVersionAnnotation] ||
+				desired.Annotations[utils.DBPasswordRotationAnnotation] != existing.Annotations[utils.DBPasswordRotationAnnotation]
+			return replace, deleteOptions, nil
+		},
+		DeleteOptions: deleteOptions,
+	}
+	return r.Reconcile(ctx, instance, params)
+}
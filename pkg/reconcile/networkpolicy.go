@@ -0,0 +1,32 @@
+package reconcile
+
+import (
+	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"context"
+	networkingv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+
+// NetworkPolicies reconciles the networkpolicy(s) required for the instance in the current context.
+// The networkpolicies are deleted when the networkPolicy of the instance is disabled.
+func NetworkPolicies(ctx context.Context, instance client.Object, params models.Params) error {
+	obj, err := Lookup(instance)
+	if err != nil {
+		return err
+	}
+
+	var desired []networkingv1.NetworkPolicy
+	if policy, ok := obj.(NetworkPolicyObject); ok {
+		desired = policy.DesiredNetworkPolicies(ctx, instance, params)
+	}
+
+	r := ResourceReconciler[*networkingv1.NetworkPolicy]{
+		Kind:     "networkpolicy",
+		Desired:  pointers(desired),
+		NewList:  func() client.ObjectList { return &networkingv1.NetworkPolicyList{} },
+		Selector: InstanceSelector(instance),
+	}
+	return r.Reconcile(ctx, instance, params)
+}
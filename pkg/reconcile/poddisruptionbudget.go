@@ -0,0 +1,31 @@
+package reconcile
+
+import (
+	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"context"
+	policyv1 "k8s.io/api/policy/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+
+// PodDisruptionBudgets reconciles the poddisruptionbudget(s) required for the instance in the current context.
+func PodDisruptionBudgets(ctx context.Context, instance client.Object, params models.Params) error {
+	obj, err := Lookup(instance)
+	if err != nil {
+		return err
+	}
+
+	var desired []policyv1.PodDisruptionBudget
+	if budget, ok := obj.(DisruptionBudgetObject); ok {
+		desired = budget.DesiredPodDisruptionBudgets(ctx, instance, params)
+	}
+
+	r := ResourceReconciler[*policyv1.PodDisruptionBudget]{
+		Kind:     "poddisruptionbudget",
+		Desired:  pointers(desired),
+		NewList:  func() client.ObjectList { return &policyv1.PodDisruptionBudgetList{} },
+		Selector: InstanceSelector(instance),
+	}
+	return r.Reconcile(ctx, instance, params)
+}
@@ -0,0 +1,213 @@
+package reconcile
+
+import (
+	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"apolloconfig.com/apollo-operator/pkg/utils"
+	"apolloconfig.com/apollo-operator/pkg/utils/naming"
+	"context"
+	"errors"
+	"fmt"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// ErrSkip is returned by a mutate function to leave an existing object untouched in this reconcile cycle.
+var ErrSkip = errors.New("skip the update")
+
+// ResourceReconciler server-side applies and prunes the objects of one resource type owned by an instance.
+// The Apollo kinds only build the desired objects, the CRUD code is shared by every resource type.
+type ResourceReconciler[T client.Object] struct {
+	// Kind names the resource in the logs and errors, e.g. "configmap".
+	Kind string
+
+	// Desired are the objects the instance should own.
+	Desired []T
+
+	// NewList returns an empty list of the resource, the listed objects which are not desired are deleted.
+	NewList func() client.ObjectList
+
+	// Selector selects the objects owned by the instance, see InstanceSelector.
+	Selector client.MatchingLabels
+
+	// Mutate is called with the existing object before desired is applied, it may adjust desired to the existing
+	// object, e.g. to keep a generated password. Returning ErrSkip leaves this object untouched.
+	// +optional
+	Mutate func(desired, existing T) error
+
+	// CreateOnly never updates existing objects, e.g. because the whole object is immutable.
+	// They are still deleted and created again when Replace reports so.
+	// +optional
+	CreateOnly bool
+
+	// Replace reports whether existing cannot be updated to desired, e.g. because of an immutable field.
+	// It is then deleted with the returned options and created again in the next reconcile cycle.
+	// +optional
+	Replace func(desired, existing T) (bool, []client.DeleteOption, error)
+
+	// DeleteOptions are used to delete the objects which are not desired anymore, e.g. the propagation policy of the
+	// jobs, which orphan their pods by default.
+	// +optional
+	DeleteOptions []client.DeleteOption
+}
+
+// InstanceSelector returns the labels of the objects managed for instance.
+func InstanceSelector(instance client.Object) client.MatchingLabels {
+	return client.MatchingLabels{
+		"app.kubernetes.io/instance":   naming.Truncate("%s.%s", 63, instance.GetNamespace(), instance.GetName()),
+		"app.kubernetes.io/managed-by": "apollo-operator",
+	}
+}
+
+// Reconcile creates or updates the desired objects, then deletes the owned objects which are not desired anymore.
+func (r ResourceReconciler[T]) Reconcile(ctx context.Context, instance client.Object, params models.Params) error {
+	// first, handle the create/update parts
+	if err := r.apply(ctx, instance, params); err != nil {
+		return fmt.Errorf("failed to reconcile the expected %ss: %w", r.Kind, err)
+	}
+
+	// then, delete the extra objects
+	if err := r.prune(ctx, instance, params); err != nil {
+		return fmt.Errorf("failed to reconcile the %ss to be deleted: %w", r.Kind, err)
+	}
+
+	return nil
+}
+
+func (r ResourceReconciler[T]) apply(ctx context.Context, instance client.Object, params models.Params) error {
+	for _, desired := range r.Desired {
+		// After establishing the OwnerReference, deleting the instance will also delete the object
+		if err := controllerutil.SetControllerReference(instance, desired, params.Scheme); err != nil {
+			return fmt.Errorf("failed to set controller reference: %w", err)
+		}
+
+		existing, err := r.get(ctx, params, desired)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get: %w", err)
+		}
+
+		if err == nil {
+			// NOTE 先判断是否需要重建，CreateOnly只跳过更新，例如schema版本变化后的job
+			if r.Replace != nil {
+				replace, opts, err := r.Replace(desired, existing)
+				if err != nil {
+					return err
+				}
+				if replace {
+					if err := params.Client.Delete(ctx, existing, opts...); err != nil {
+						return fmt.Errorf("failed to delete %s: %w", r.Kind, err)
+					}
+					params.Log.V(2).Info("deleted, it will be created in the next reconcile cycle", r.Kind+".name", existing.GetName(), r.Kind+".namespace", existing.GetNamespace())
+					continue
+				}
+			}
+
+			if r.CreateOnly {
+				continue
+			}
+
+			if r.Mutate != nil {
+				if err := r.Mutate(desired, existing); err != nil {
+					if errors.Is(err, ErrSkip) {
+						continue
+					}
+					return err
+				}
+			}
+		}
+
+		// NOTE 使用server-side apply，只拥有desired中设置的字段，其他字段可以由HPA、kubectl等修改
+		if err := utils.Apply(ctx, params.Client, params.Scheme, desired); err != nil {
+			return fmt.Errorf("failed to apply changes: %w", err)
+		}
+
+		params.Log.V(2).Info("applied", r.Kind+".name", desired.GetName(), r.Kind+".namespace", desired.GetNamespace())
+	}
+
+	return nil
+}
+
+// get reads the object named like desired into a new object of the same type, or of the same kind for unstructured objects.
+func (r ResourceReconciler[T]) get(ctx context.Context, params models.Params, desired T) (T, error) {
+	existing := reflect.New(reflect.TypeOf(desired).Elem()).Interface().(T)
+	if u, ok := any(existing).(*unstructured.Unstructured); ok {
+		// NOTE 非结构化对象需要GVK才能读取，例如cert-manager的certificate
+		u.SetGroupVersionKind(desired.GetObjectKind().GroupVersionKind())
+	}
+	nns := types.NamespacedName{Namespace: desired.GetNamespace(), Name: desired.GetName()}
+	return existing, params.Client.Get(ctx, nns, existing)
+}
+
+func (r ResourceReconciler[T]) prune(ctx context.Context, instance client.Object, params models.Params) error {
+	list := r.NewList()
+	if err := params.Client.List(ctx, list, client.InNamespace(instance.GetNamespace()), r.Selector); err != nil {
+		return fmt.Errorf("failed to list %s : %w", r.Kind, err)
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return fmt.Errorf("failed to list %s : %w", r.Kind, err)
+	}
+
+	keep := make(map[types.NamespacedName]bool, len(r.Desired))
+	for _, desired := range r.Desired {
+		keep[types.NamespacedName{Namespace: desired.GetNamespace(), Name: desired.GetName()}] = true
+	}
+
+	// Delete parts that are not expected
+	for _, item := range items {
+		existing, ok := item.(client.Object)
+		if !ok || keep[types.NamespacedName{Namespace: existing.GetNamespace(), Name: existing.GetName()}] {
+			continue
+		}
+		if err := params.Client.Delete(ctx, existing, r.DeleteOptions...); err != nil {
+			return fmt.Errorf("failed to delete: %w", err)
+		}
+		params.Log.V(2).Info("deleted", r.Kind+".name", existing.GetName(), r.Kind+".namespace", existing.GetNamespace())
+	}
+
+	return nil
+}
+
+// optionalResources reconciles the unstructured objects of an API which may not be installed, e.g. the certificates of
+// cert-manager. Nothing is done when the API is missing and no object is desired, otherwise missing is reported.
+func optionalResources(ctx context.Context, instance client.Object, params models.Params, kind string,
+	gvk schema.GroupVersionKind, desired []unstructured.Unstructured, missing string) error {
+	if _, err := params.Client.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+		if !meta.IsNoMatchError(err) {
+			return fmt.Errorf("failed to discover the %ss: %w", kind, err)
+		}
+		if len(desired) > 0 {
+			return fmt.Errorf("%s: %w", missing, err)
+		}
+		return nil
+	}
+
+	r := ResourceReconciler[*unstructured.Unstructured]{
+		Kind:    kind,
+		Desired: pointers(desired),
+		NewList: func() client.ObjectList {
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+			return list
+		},
+		Selector: InstanceSelector(instance),
+	}
+	return r.Reconcile(ctx, instance, params)
+}
+
+// pointers returns pointers to the objects built by the Desired methods of the Apollo kinds.
+func pointers[E any, T interface {
+	*E
+	client.Object
+}](objects []E) []T {
+	result := make([]T, 0, len(objects))
+	for i := range objects {
+		result = append(result, T(&objects[i]))
+	}
+	return result
+}
@@ -0,0 +1,152 @@
+package reconcile
+
+import (
+	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// applyRecorder records the server-side applies, which the fake client does not support, and the options of the deletes.
+type applyRecorder struct {
+	client.Client
+	applied []string
+	deleted map[string]*client.DeleteOptions
+}
+
+func (c *applyRecorder) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch == client.Apply {
+		c.applied = append(c.applied, obj.GetName())
+		return nil
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *applyRecorder) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if c.deleted == nil {
+		c.deleted = map[string]*client.DeleteOptions{}
+	}
+	c.deleted[obj.GetName()] = (&client.DeleteOptions{}).ApplyOptions(opts)
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func TestResourceReconcilerApply(t *testing.T) {
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "default", UID: "owner-uid"}}
+	existing := func() *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "default", Labels: InstanceSelector(owner)},
+			Data:       map[string]string{"version": "1"},
+		}
+	}
+	desired := func() *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "default", Labels: InstanceSelector(owner)},
+			Data:       map[string]string{"version": "2"},
+		}
+	}
+	replaceWhenChanged := func(desired, existing *corev1.ConfigMap) (bool, []client.DeleteOption, error) {
+		return desired.Data["version"] != existing.Data["version"], nil, nil
+	}
+	never := func(desired, existing *corev1.ConfigMap) (bool, []client.DeleteOption, error) {
+		return false, nil, nil
+	}
+
+	tests := []struct {
+		name        string
+		existing    bool
+		createOnly  bool
+		replace     func(desired, existing *corev1.ConfigMap) (bool, []client.DeleteOption, error)
+		mutate      func(desired, existing *corev1.ConfigMap) error
+		wantApplied bool
+		wantDeleted bool
+	}{
+		{name: "creates a missing object", wantApplied: true},
+		{name: "creates a missing create-only object", createOnly: true, wantApplied: true},
+		{name: "updates an existing object", existing: true, wantApplied: true},
+		{name: "leaves an existing create-only object", existing: true, createOnly: true, replace: never},
+		{name: "replaces a create-only object", existing: true, createOnly: true, replace: replaceWhenChanged, wantDeleted: true},
+		{name: "replaces an object", existing: true, replace: replaceWhenChanged, wantDeleted: true},
+		{name: "updates an object which is not replaced", existing: true, replace: never, wantApplied: true},
+		{
+			name:     "skips an object on ErrSkip",
+			existing: true,
+			mutate:   func(desired, existing *corev1.ConfigMap) error { return ErrSkip },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(scheme.Scheme)
+			if tt.existing {
+				builder = builder.WithObjects(existing())
+			}
+			c := &applyRecorder{Client: builder.Build()}
+			params := models.Params{Client: c, Scheme: scheme.Scheme, Log: logr.Discard()}
+
+			r := ResourceReconciler[*corev1.ConfigMap]{
+				Kind:       "configmap",
+				Desired:    []*corev1.ConfigMap{desired()},
+				NewList:    func() client.ObjectList { return &corev1.ConfigMapList{} },
+				Selector:   InstanceSelector(owner),
+				CreateOnly: tt.createOnly,
+				Replace:    tt.replace,
+				Mutate:     tt.mutate,
+			}
+			if err := r.apply(context.Background(), owner, params); err != nil {
+				t.Fatalf("apply() error = %v", err)
+			}
+
+			if applied := len(c.applied) > 0; applied != tt.wantApplied {
+				t.Errorf("applied = %v, want %v", applied, tt.wantApplied)
+			}
+			err := c.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "job"}, &corev1.ConfigMap{})
+			if deleted := tt.existing && k8serrors.IsNotFound(err); deleted != tt.wantDeleted {
+				t.Errorf("deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+		})
+	}
+}
+
+func TestResourceReconcilerPrune(t *testing.T) {
+	owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "default", UID: "owner-uid"}}
+	object := func(name string, labels map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels}}
+	}
+	c := &applyRecorder{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		object("kept", InstanceSelector(owner)),
+		object("extra", InstanceSelector(owner)),
+		object("foreign", map[string]string{"app": "other"}),
+	).Build()}
+	params := models.Params{Client: c, Scheme: scheme.Scheme, Log: logr.Discard()}
+
+	r := ResourceReconciler[*corev1.ConfigMap]{
+		Kind:          "configmap",
+		Desired:       []*corev1.ConfigMap{object("kept", InstanceSelector(owner))},
+		NewList:       func() client.ObjectList { return &corev1.ConfigMapList{} },
+		Selector:      InstanceSelector(owner),
+		DeleteOptions: []client.DeleteOption{client.PropagationPolicy(metav1.DeletePropagationBackground)},
+	}
+	if err := r.prune(context.Background(), owner, params); err != nil {
+		t.Fatalf("prune() error = %v", err)
+	}
+
+	for name, wantExists := range map[string]bool{"kept": true, "extra": false, "foreign": true} {
+		err := c.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: name}, &corev1.ConfigMap{})
+		if exists := err == nil; exists != wantExists {
+			t.Errorf("%s exists = %v, want %v (err %v)", name, exists, wantExists, err)
+		}
+	}
+	if len(c.deleted) != 1 {
+		t.Fatalf("deleted = %v, want only extra", c.deleted)
+	}
+	if opts := c.deleted["extra"]; opts == nil || opts.PropagationPolicy == nil || *opts.PropagationPolicy != metav1.DeletePropagationBackground {
+		t.Errorf("delete options of extra = %+v, want the background propagation", opts)
+	}
+}
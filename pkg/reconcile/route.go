@@ -0,0 +1,30 @@
+package reconcile
+
+import (
+	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"apolloconfig.com/apollo-operator/pkg/utils"
+	"context"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NOTE 设置spec.host需要routes/custom-host权限
+// +kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=route.openshift.io,resources=routes/custom-host,verbs=create;update;patch
+
+// Routes reconciles the OpenShift route(s) required for the instance in the current context.
+// Nothing is done on the clusters without the route.openshift.io API when no component is exposed through it.
+func Routes(ctx context.Context, instance client.Object, params models.Params) error {
+	obj, err := Lookup(instance)
+	if err != nil {
+		return err
+	}
+
+	var desired []unstructured.Unstructured
+	if route, ok := obj.(RouteObject); ok {
+		desired = route.DesiredRoutes(ctx, instance, params)
+	}
+
+	return optionalResources(ctx, instance, params, "route", utils.RouteGVK, desired,
+		"the route.openshift.io API is not installed, the routes cannot be created")
+}
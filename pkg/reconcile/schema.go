@@ -0,0 +1,21 @@
+package reconcile
+
+import (
+	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"context"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Schema waits for the schema job of the instance in the current context, the deployments are only reconciled once the schema is ready.
+func Schema(ctx context.Context, instance client.Object, params models.Params) error {
+	handler, err := Lookup(instance)
+	if err != nil {
+		return err
+	}
+
+	// NOTE 没有schema的类型直接跳过
+	if obj, ok := handler.(SchemaObject); ok {
+		return obj.WaitForSchema(ctx, instance, params)
+	}
+	return nil
+}
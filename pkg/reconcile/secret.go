@@ -2,7 +2,11 @@ package reconcile
 
 import (
 	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
+	"apolloconfig.com/apollo-operator/pkg/utils"
 	"context"
+	"fmt"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -10,6 +14,95 @@ import (
 
 // Secret reconciles the secret(s) required for the instance in the current context.
 func Secret(ctx context.Context, instance client.Object, params models.Params) error {
+	obj, err := Lookup(instance)
+	if err != nil {
+		return err
+	}
 
-	return nil
+	desired := obj.DesiredSecrets(ctx, instance, params)
+
+	r := ResourceReconciler[*corev1.Secret]{
+		Kind:     "secret",
+		Desired:  pointers(desired),
+		NewList:  func() client.ObjectList { return &corev1.SecretList{} },
+		Selector: InstanceSelector(instance),
+		Mutate: func(desired, existing *corev1.Secret) error {
+			// NOTE 带有轮换注解的secret保存operator生成的密码，轮换job成功后才使用新密码
+			if _, ok := desired.Annotations[utils.DBPasswordRotationAnnotation]; !ok {
+				return nil
+			}
+			completed, err := rotationCompleted(ctx, instance, params, existing.Annotations[utils.DBPasswordRotationAnnotation])
+			if err != nil {
+				return err
+			}
+			if keepPassword(desired, existing, completed) {
+				params.Recorder.Event(instance, "Normal", "PasswordRotated", fmt.Sprintf("Apollo database password rotated - %s/%s", desired.Namespace, desired.Name))
+			}
+			return nil
+		},
+	}
+	return r.Reconcile(ctx, instance, params)
+}
+
+// rotationCompleted reports whether the password rotation job started for token succeeded.
+func rotationCompleted(ctx context.Context, instance client.Object, params models.Params, token string) (bool, error) {
+	if token == "" {
+		return false, nil
+	}
+	jobs := &batchv1.JobList{}
+	if err := params.Client.List(ctx, jobs, client.InNamespace(instance.GetNamespace()), InstanceSelector(instance)); err != nil {
+		return false, fmt.Errorf("failed to list the rotation jobs: %w", err)
+	}
+	for _, job := range jobs.Items {
+		if job.Annotations[utils.DBPasswordRotationAnnotation] == token && job.Status.Succeeded > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// keepPassword keeps the generated password stable across reconciles and reports whether a rotation was completed.
+// A rotation requested by a new annotation stages the password of desired as pending password, the password in use
+// is only replaced by it once the rotation job changed it in the database, i.e. when completed is true.
+func keepPassword(desired, existing *corev1.Secret, completed bool) bool {
+	current := existing.Data[utils.SecretPasswordKey]
+	if len(current) == 0 {
+		return false
+	}
+	pending := existing.Data[utils.SecretPendingPasswordKey]
+	previous := existing.Data[utils.SecretPreviousPasswordKey]
+	token := existing.Annotations[utils.DBPasswordRotationAnnotation]
+	rotated := existing.Annotations[utils.DBPasswordRotatedAnnotation]
+
+	promoted := false
+	if len(pending) > 0 && completed {
+		previous, current, pending = current, pending, nil
+		rotated = token
+		promoted = true
+	}
+
+	if requested := desired.Annotations[utils.DBPasswordRotationAnnotation]; requested != token {
+		// NOTE 上一次轮换未完成时继续使用已暂存的密码，轮换job对已生效的新密码是幂等的
+		if len(pending) == 0 {
+			pending = desired.Data[utils.SecretPasswordKey]
+		}
+		// NOTE 生成新密码失败时，保留旧的轮换标记，下次调谐时再轮换
+		if len(pending) > 0 {
+			token = requested
+		}
+	}
+
+	desired.Data[utils.SecretPasswordKey] = current
+	delete(desired.Data, utils.SecretPendingPasswordKey)
+	if len(pending) > 0 {
+		desired.Data[utils.SecretPendingPasswordKey] = pending
+	}
+	if len(previous) > 0 {
+		desired.Data[utils.SecretPreviousPasswordKey] = previous
+	}
+	desired.Annotations[utils.DBPasswordRotationAnnotation] = token
+	if rotated != "" {
+		desired.Annotations[utils.DBPasswordRotatedAnnotation] = rotated
+	}
+	return promoted
 }
@@ -0,0 +1,121 @@
+package reconcile
+
+import (
+	"apolloconfig.com/apollo-operator/pkg/utils"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// desiredSecret is the secret built by a kind, with a freshly generated password and the rotation token of the instance.
+func desiredSecret(token, password string) *corev1.Secret {
+	data := map[string][]byte{utils.SecretUsernameKey: []byte("root")}
+	if password != "" {
+		data[utils.SecretPasswordKey] = []byte(password)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{utils.DBPasswordRotationAnnotation: token}},
+		Data:       data,
+	}
+}
+
+func TestKeepPassword(t *testing.T) {
+	type step struct {
+		name      string
+		token     string
+		generated string
+		completed bool
+
+		wantPassword string
+		wantPending  string
+		wantPrevious string
+		wantToken    string
+		wantRotated  string
+		wantPromoted bool
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "keeps the password without rotation",
+			steps: []step{
+				{name: "reconcile", generated: "b", wantPassword: "a"},
+			},
+		},
+		{
+			name: "rotates twice",
+			steps: []step{
+				{name: "request", token: "1", generated: "b", wantPassword: "a", wantPending: "b", wantToken: "1"},
+				{name: "job running", token: "1", generated: "c", wantPassword: "a", wantPending: "b", wantToken: "1"},
+				{name: "job succeeded", token: "1", generated: "d", completed: true,
+					wantPassword: "b", wantPrevious: "a", wantToken: "1", wantRotated: "1", wantPromoted: true},
+				{name: "idle", token: "1", generated: "e", completed: true,
+					wantPassword: "b", wantPrevious: "a", wantToken: "1", wantRotated: "1"},
+				{name: "second request", token: "2", generated: "f",
+					wantPassword: "b", wantPending: "f", wantPrevious: "a", wantToken: "2", wantRotated: "1"},
+				{name: "second job running", token: "2", generated: "g",
+					wantPassword: "b", wantPending: "f", wantPrevious: "a", wantToken: "2", wantRotated: "1"},
+				{name: "second job succeeded", token: "2", generated: "h", completed: true,
+					wantPassword: "f", wantPrevious: "b", wantToken: "2", wantRotated: "2", wantPromoted: true},
+			},
+		},
+		{
+			name: "keeps the pending password of an unfinished rotation",
+			steps: []step{
+				{name: "request", token: "1", generated: "b", wantPassword: "a", wantPending: "b", wantToken: "1"},
+				{name: "request again", token: "2", generated: "c", wantPassword: "a", wantPending: "b", wantToken: "2"},
+				{name: "job succeeded", token: "2", generated: "d", completed: true,
+					wantPassword: "b", wantPrevious: "a", wantToken: "2", wantRotated: "2", wantPromoted: true},
+			},
+		},
+		{
+			name: "promotes before staging the next rotation",
+			steps: []step{
+				{name: "request", token: "1", generated: "b", wantPassword: "a", wantPending: "b", wantToken: "1"},
+				{name: "job succeeded and request again", token: "2", generated: "c", completed: true,
+					wantPassword: "b", wantPending: "c", wantPrevious: "a", wantToken: "2", wantRotated: "1", wantPromoted: true},
+			},
+		},
+		{
+			name: "retries a rotation without a generated password",
+			steps: []step{
+				{name: "request", token: "1", wantPassword: "a"},
+				{name: "retry", token: "1", generated: "b", wantPassword: "a", wantPending: "b", wantToken: "1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := desiredSecret("", "a")
+			for _, s := range tt.steps {
+				desired := desiredSecret(s.token, s.generated)
+				promoted := keepPassword(desired, existing, s.completed)
+
+				if promoted != s.wantPromoted {
+					t.Errorf("%s: promoted = %v, want %v", s.name, promoted, s.wantPromoted)
+				}
+				for key, want := range map[string]string{
+					utils.SecretPasswordKey:         s.wantPassword,
+					utils.SecretPendingPasswordKey:  s.wantPending,
+					utils.SecretPreviousPasswordKey: s.wantPrevious,
+				} {
+					if got := string(desired.Data[key]); got != want {
+						t.Errorf("%s: %s = %q, want %q", s.name, key, got, want)
+					}
+				}
+				for key, want := range map[string]string{
+					utils.DBPasswordRotationAnnotation: s.wantToken,
+					utils.DBPasswordRotatedAnnotation:  s.wantRotated,
+				} {
+					if got := desired.Annotations[key]; got != want {
+						t.Errorf("%s: annotation %s = %q, want %q", s.name, key, got, want)
+					}
+				}
+				existing = desired
+			}
+		})
+	}
+}
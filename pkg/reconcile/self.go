@@ -3,6 +3,7 @@ package reconcile
 import (
 	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
 	"context"
+	"fmt"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -10,6 +11,14 @@ import (
 // making params.Instance obsolete. Default values should be set in the Defaulter webhook, this should only be used
 // for the Status, which can't be set by the defaulter.
 func Self(ctx context.Context, instance client.Object, params models.Params) error {
+	obj, err := Lookup(instance)
+	if err != nil {
+		return err
+	}
+
+	if err := obj.UpdateStatus(ctx, instance, params); err != nil {
+		return fmt.Errorf("failed to update the status: %w", err)
+	}
 
 	return nil
 }
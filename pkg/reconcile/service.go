@@ -3,7 +3,7 @@ package reconcile
 import (
 	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
 	"context"
-	"fmt"
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -11,29 +11,18 @@ import (
 
 // Services reconciles the service(s) required for the instance in the current context.
 func Services(ctx context.Context, instance client.Object, params models.Params) error {
-	var obj ApolloObject
-
-	kind := instance.GetObjectKind().GroupVersionKind().Kind
-	switch kind {
-	case "ApolloPortal":
-		obj = ApolloPortal()
-	case "ApolloEnvironment":
-		obj = ApolloEnvironment()
-	case "Apollo":
-		obj = ApolloAllInOne()
+	obj, err := Lookup(instance)
+	if err != nil {
+		return err
 	}
 
 	desired := obj.DesiredServices(ctx, instance, params)
 
-	// first, handle the create/update parts
-	if err := obj.ExpectedServices(ctx, instance, params, desired); err != nil {
-		return fmt.Errorf("failed to reconcile the expected services: %w", err)
-	}
-
-	// then, delete the extra objects
-	if err := obj.DeleteServices(ctx, instance, params, desired); err != nil {
-		return fmt.Errorf("failed to reconcile the services to be deleted: %w", err)
+	r := ResourceReconciler[*corev1.Service]{
+		Kind:     "service",
+		Desired:  pointers(desired),
+		NewList:  func() client.ObjectList { return &corev1.ServiceList{} },
+		Selector: InstanceSelector(instance),
 	}
-
-	return nil
+	return r.Reconcile(ctx, instance, params)
 }
@@ -3,13 +3,30 @@ package reconcile
 import (
 	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
 	"context"
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
 
 // ServiceAccounts reconciles the service account(s) required for the instance in the current context.
+// The service account of a component is deleted when it switches to an existing one.
 func ServiceAccounts(ctx context.Context, instance client.Object, params models.Params) error {
+	obj, err := Lookup(instance)
+	if err != nil {
+		return err
+	}
 
-	return nil
+	var desired []corev1.ServiceAccount
+	if account, ok := obj.(ServiceAccountObject); ok {
+		desired = account.DesiredServiceAccounts(ctx, instance, params)
+	}
+
+	r := ResourceReconciler[*corev1.ServiceAccount]{
+		Kind:     "serviceaccount",
+		Desired:  pointers(desired),
+		NewList:  func() client.ObjectList { return &corev1.ServiceAccountList{} },
+		Selector: InstanceSelector(instance),
+	}
+	return r.Reconcile(ctx, instance, params)
 }
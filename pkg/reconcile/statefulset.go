@@ -4,26 +4,168 @@ import (
 	"apolloconfig.com/apollo-operator/pkg/reconcile/models"
 	"context"
 	"fmt"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // +kubebuilder:rbac:groups="apps",resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;update;patch
 
 // StatefulSet reconciles the statefulset required for the instance in the current context.
 func StatefulSet(ctx context.Context, instance client.Object, params models.Params) error {
 
-	obj := ApolloAllInOne()
+	handler, err := Lookup(instance)
+	if err != nil {
+		return err
+	}
+	obj, ok := handler.(StatefulSetObject)
+	if !ok {
+		return fmt.Errorf("%T does not run a statefulset", instance)
+	}
 
 	desired := obj.DesiredStatefulSets(ctx, instance, params)
 
-	if err := obj.ExpectedStatefulSets(ctx, instance, params, desired); err != nil {
-		return fmt.Errorf("failed to reconcile the expected statefulset: %w", err)
+	r := ResourceReconciler[*appsv1.StatefulSet]{
+		Kind:     "statefulset",
+		Desired:  pointers(desired),
+		NewList:  func() client.ObjectList { return &appsv1.StatefulSetList{} },
+		Selector: InstanceSelector(instance),
+		Replace: func(desired, existing *appsv1.StatefulSet) (bool, []client.DeleteOption, error) {
+			// Check for immutable fields. If set, we cannot modify the stateful set, otherwise we will face reconciliation error.
+			needsDeletion, fieldName := hasImmutableFieldChange(desired, existing)
+			if !needsDeletion {
+				return false, nil, nil
+			}
+			params.Log.V(2).Info("Immutable field change detected, trying to delete, the new statefulset will be created in the next reconcile cycle",
+				"field", fieldName, "statefulset.name", existing.Name, "statefulset.namespace", existing.Namespace)
+
+			var opts []client.DeleteOption
+			if onlyVolumeClaimsGrew(desired, existing) {
+				if err := expandVolumeClaims(ctx, params, desired, existing); err != nil {
+					return false, nil, err
+				}
+				// NOTE 只是扩容的话，保留pod继续运行，下次调谐时使用新的模板重新创建statefulset
+				opts = append(opts, client.PropagationPolicy(metav1.DeletePropagationOrphan))
+			}
+			return true, opts, nil
+		},
 	}
+	return r.Reconcile(ctx, instance, params)
+}
+
+func hasImmutableFieldChange(desired, existing *appsv1.StatefulSet) (bool, string) {
+	if !apiequality.Semantic.DeepEqual(desired.Spec.Selector, existing.Spec.Selector) {
+		return true, "Spec.Selector"
+	}
+
+	if hasVolumeClaimsTemplatesChanged(desired, existing) {
+		return true, "Spec.VolumeClaimTemplates"
+	}
+
+	return false, ""
+}
+
+// hasVolumeClaimsTemplatesChanged if volume claims template change has been detected.
+// We need to do this manually due to some fields being automatically filled by the API server
+// and these needs to be excluded from the comparison to prevent false positives.
+func hasVolumeClaimsTemplatesChanged(desired, existing *appsv1.StatefulSet) bool {
+	if len(desired.Spec.VolumeClaimTemplates) != len(existing.Spec.VolumeClaimTemplates) {
+		return true
+	}
+
+	for i := range desired.Spec.VolumeClaimTemplates {
+		// VolumeMode is automatically set by the API server, so if it is not set in the CR, assume it's the same as the existing one.
+		if desired.Spec.VolumeClaimTemplates[i].Spec.VolumeMode == nil || *desired.Spec.VolumeClaimTemplates[i].Spec.VolumeMode == "" {
+			desired.Spec.VolumeClaimTemplates[i].Spec.VolumeMode = existing.Spec.VolumeClaimTemplates[i].Spec.VolumeMode
+		}
 
-	// then, delete the extra objects
-	if err := obj.DeleteStatefulSets(ctx, instance, params, desired); err != nil {
-		return fmt.Errorf("failed to reconcile the statefulsets to be deleted: %w", err)
+		if desired.Spec.VolumeClaimTemplates[i].Name != existing.Spec.VolumeClaimTemplates[i].Name {
+			return true
+		}
+		if !apiequality.Semantic.DeepEqual(desired.Spec.VolumeClaimTemplates[i].Annotations, existing.Spec.VolumeClaimTemplates[i].Annotations) {
+			return true
+		}
+		if !apiequality.Semantic.DeepEqual(desired.Spec.VolumeClaimTemplates[i].Spec, existing.Spec.VolumeClaimTemplates[i].Spec) {
+			return true
+		}
 	}
 
+	return false
+}
+
+// onlyVolumeClaimsGrew reports whether the volume claim templates only differ by a larger storage request,
+// such a change is applied to the existing PVCs in place.
+func onlyVolumeClaimsGrew(desired, existing *appsv1.StatefulSet) bool {
+	if len(desired.Spec.VolumeClaimTemplates) != len(existing.Spec.VolumeClaimTemplates) {
+		return false
+	}
+
+	grew := false
+	for i := range desired.Spec.VolumeClaimTemplates {
+		desiredSize := desired.Spec.VolumeClaimTemplates[i].Spec.Resources.Requests[corev1.ResourceStorage]
+		existingSize := existing.Spec.VolumeClaimTemplates[i].Spec.Resources.Requests[corev1.ResourceStorage]
+		switch desiredSize.Cmp(existingSize) {
+		case -1:
+			return false
+		case 1:
+			grew = true
+		}
+
+		// compare the templates without the storage request
+		template := existing.Spec.VolumeClaimTemplates[i].DeepCopy()
+		if template.Spec.Resources.Requests == nil {
+			template.Spec.Resources.Requests = corev1.ResourceList{}
+		}
+		template.Spec.Resources.Requests[corev1.ResourceStorage] = desiredSize
+		if template.Name != desired.Spec.VolumeClaimTemplates[i].Name ||
+			!apiequality.Semantic.DeepEqual(template.Annotations, desired.Spec.VolumeClaimTemplates[i].Annotations) ||
+			!apiequality.Semantic.DeepEqual(template.Spec, desired.Spec.VolumeClaimTemplates[i].Spec) {
+			return false
+		}
+	}
+	return grew
+}
+
+// expandVolumeClaims raises the storage request of the PVCs created by the statefulset to the one of desired,
+// the storage class must allow volume expansion.
+func expandVolumeClaims(ctx context.Context, params models.Params, desired, existing *appsv1.StatefulSet) error {
+	var replicas int32 = 1
+	if existing.Spec.Replicas != nil {
+		replicas = *existing.Spec.Replicas
+	}
+
+	for _, template := range desired.Spec.VolumeClaimTemplates {
+		size := template.Spec.Resources.Requests[corev1.ResourceStorage]
+		for ordinal := int32(0); ordinal < replicas; ordinal++ {
+			// NOTE statefulset创建的pvc的名字为 <template>-<statefulset>-<ordinal>
+			pvc := &corev1.PersistentVolumeClaim{}
+			nns := types.NamespacedName{Namespace: existing.Namespace, Name: fmt.Sprintf("%s-%s-%d", template.Name, existing.Name, ordinal)}
+			if err := params.Client.Get(ctx, nns, pvc); err != nil {
+				if k8serrors.IsNotFound(err) {
+					continue
+				}
+				return fmt.Errorf("failed to get pvc: %w", err)
+			}
+
+			current := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+			if size.Cmp(current) <= 0 {
+				continue
+			}
+			updated := pvc.DeepCopy()
+			if updated.Spec.Resources.Requests == nil {
+				updated.Spec.Resources.Requests = corev1.ResourceList{}
+			}
+			updated.Spec.Resources.Requests[corev1.ResourceStorage] = size
+			if err := params.Client.Patch(ctx, updated, client.MergeFrom(pvc)); err != nil {
+				return fmt.Errorf("failed to expand pvc %s: %w", pvc.Name, err)
+			}
+			params.Log.V(2).Info("expanded", "pvc.name", pvc.Name, "pvc.namespace", pvc.Namespace, "size", size.String())
+		}
+	}
 	return nil
 }
@@ -0,0 +1,181 @@
+// Package schema embeds the versioned database scripts of apollo and builds the job applying them.
+package schema
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	corev1 "k8s.io/api/core/v1"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Databases whose schema can be managed by the operator, named after the script directories.
+const (
+	ConfigDB = "apolloconfigdb"
+	PortalDB = "apolloportaldb"
+)
+
+const (
+	// InitScript is the key of the script creating the schema in an empty database.
+	InitScript = "init.sql"
+	// MountPath is where the schema job mounts the scripts.
+	MountPath = "/schema"
+	// VersionTable records the schema versions applied by the operator.
+	VersionTable = "OperatorSchemaVersion"
+)
+
+const (
+	scriptsDir  = "scripts"
+	deltaScript = "delta.sql"
+	deltaPrefix = "delta-"
+	deltaSuffix = ".sql"
+)
+
+// scripts holds scripts/<database>/<version>/init.sql, creating the schema of a version from scratch,
+// and scripts/<database>/<version>/delta.sql, migrating the schema of the previous version to this one.
+//
+//go:embed scripts
+var scripts embed.FS
+
+// ImageVersion returns the tag of an image reference, e.g. 2.1.0 for apolloconfig/apollo-portal:2.1.0.
+func ImageVersion(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+	return ""
+}
+
+// Versions returns the versions of database up to and including target, in ascending order.
+func Versions(database, target string) ([]string, error) {
+	entries, err := fs.ReadDir(scripts, path.Join(scriptsDir, database))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the %s scripts: %w", database, err)
+	}
+
+	var versions []string
+	found := false
+	for _, entry := range entries {
+		if !entry.IsDir() || compareVersions(entry.Name(), target) > 0 {
+			continue
+		}
+		found = found || entry.Name() == target
+		versions = append(versions, entry.Name())
+	}
+	if !found {
+		return nil, fmt.Errorf("no %s schema is embedded for version %q", database, target)
+	}
+	sort.Slice(versions, func(i, j int) bool { return compareVersions(versions[i], versions[j]) < 0 })
+	return versions, nil
+}
+
+// Scripts returns the scripts needed to create or migrate database to target, keyed as expected by the job:
+// InitScript for an empty database, and delta-<version>.sql for every migration up to target.
+func Scripts(database, target string) (map[string]string, error) {
+	versions, err := Versions(database, target)
+	if err != nil {
+		return nil, err
+	}
+
+	initScript, err := fs.ReadFile(scripts, path.Join(scriptsDir, database, target, InitScript))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the %s init script of version %s: %w", database, target, err)
+	}
+	data := map[string]string{InitScript: string(initScript)}
+	for _, version := range versions {
+		delta, err := fs.ReadFile(scripts, path.Join(scriptsDir, database, version, deltaScript))
+		if err != nil {
+			// NOTE 第一个版本没有delta脚本
+			continue
+		}
+		data[deltaPrefix+version+deltaSuffix] = string(delta)
+	}
+	return data, nil
+}
+
+// compareVersions compares dotted numeric versions, non numeric parts compare as strings.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y string
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		xi, xerr := strconv.Atoi(x)
+		yi, yerr := strconv.Atoi(y)
+		switch {
+		case xerr == nil && yerr == nil && xi != yi:
+			if xi < yi {
+				return -1
+			}
+			return 1
+		case (xerr != nil || yerr != nil) && x != y:
+			return strings.Compare(x, y)
+		}
+	}
+	return 0
+}
+
+// Command is run by the schema job. It creates the database when missing, applies the init script to an empty database,
+// or the delta scripts newer than the version recorded in the version table, and records the applied version.
+// A database holding apollo tables but no version table is adopted as SCHEMA_VERSION without running any script.
+const Command = `set -e
+mysql_db() { mysql -h"${DB_HOST}" -P"${DB_PORT}" -u"${DB_USERNAME}" "$@"; }
+export MYSQL_PWD="${DB_PASSWORD}"
+until mysql_db -e "SELECT 1" >/dev/null; do echo "waiting for the database"; sleep 2; done
+mysql_db -e "CREATE DATABASE IF NOT EXISTS ` + "`${DB_NAME}`" + ` DEFAULT CHARACTER SET = utf8mb4"
+mysql_db "${DB_NAME}" -e "CREATE TABLE IF NOT EXISTS ` + "`" + VersionTable + "`" + ` (Id int NOT NULL AUTO_INCREMENT, Version varchar(32) NOT NULL, AppliedAt timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, PRIMARY KEY (Id))"
+record() { mysql_db "${DB_NAME}" -e "INSERT INTO ` + VersionTable + ` (Version) VALUES ('$1')"; }
+current=$(mysql_db "${DB_NAME}" -N -e "SELECT Version FROM ` + VersionTable + ` ORDER BY Id DESC LIMIT 1")
+if [ -z "${current}" ]; then
+  tables=$(mysql_db -N -e "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema='${DB_NAME}' AND table_name='App'")
+  if [ "${tables}" = "0" ]; then
+    echo "creating the schema of version ${SCHEMA_VERSION}"
+    mysql_db "${DB_NAME}" < ` + MountPath + `/` + InitScript + `
+  else
+    echo "adopting the existing schema as version ${SCHEMA_VERSION}"
+  fi
+  record "${SCHEMA_VERSION}"
+  exit 0
+fi
+if [ "${current}" = "${SCHEMA_VERSION}" ]; then
+  echo "schema is already at version ${SCHEMA_VERSION}"
+  exit 0
+fi
+apply=false
+for version in ${SCHEMA_VERSIONS}; do
+  if [ "${apply}" = "true" ]; then
+    if [ -f "` + MountPath + `/` + deltaPrefix + `${version}` + deltaSuffix + `" ]; then
+      echo "migrating the schema to version ${version}"
+      mysql_db "${DB_NAME}" < "` + MountPath + `/` + deltaPrefix + `${version}` + deltaSuffix + `"
+    fi
+    record "${version}"
+  fi
+  if [ "${version}" = "${current}" ]; then apply=true; fi
+done
+if [ "${apply}" != "true" ]; then
+  echo "no migration from schema version ${current} to ${SCHEMA_VERSION}"
+  exit 1
+fi
+`
+
+// Env builds the environment read by Command.
+func Env(host string, port int32, dbName, version string, versions []string, username, password *corev1.SecretKeySelector) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{Name: "DB_HOST", Value: host},
+		{Name: "DB_PORT", Value: strconv.Itoa(int(port))},
+		{Name: "DB_NAME", Value: dbName},
+		{Name: "DB_USERNAME", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: username}},
+		{Name: "DB_PASSWORD", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: password}},
+		{Name: "SCHEMA_VERSION", Value: version},
+		{Name: "SCHEMA_VERSIONS", Value: strings.Join(versions, " ")},
+	}
+}
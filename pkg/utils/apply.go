@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// Apply server-side applies obj with the FieldManager of the operator, obj holds the applied object afterwards.
+// Only the fields set in obj are owned by the operator, the ownership of those is forced, so the fields left empty,
+// e.g. the replicas of an autoscaled deployment or the annotations added by kubectl, can be owned by other managers.
+func Apply(ctx context.Context, c client.Client, scheme *runtime.Scheme, obj client.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return fmt.Errorf("failed to get the kind of %s: %w", obj.GetName(), err)
+	}
+	// NOTE apply的对象必须带有apiVersion和kind，且不能带managedFields
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	obj.SetManagedFields(nil)
+	obj.SetResourceVersion("")
+	return c.Patch(ctx, obj, client.Apply, client.FieldOwner(FieldManager), client.ForceOwnership)
+}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Database describes the connection to a database backed up or restored by the operator.
+type Database struct {
+	Host     string
+	Port     int32
+	Name     string
+	Username *corev1.SecretKeySelector
+	Password *corev1.SecretKeySelector
+}
+
+// Replicas returns the replicas of a Deployment of instance, zero while a restore is in progress.
+// It is nil when the Deployment is scaled by a HorizontalPodAutoscaler, so that the applied Deployment does not own them.
+func Replicas(instance client.Object, replicas int32, autoscaling apolloiov1alpha1.Autoscaling) *int32 {
+	if _, ok := instance.GetAnnotations()[RestoreAnnotation]; ok {
+		// NOTE replicas为0时HPA不会扩容
+		return new(int32)
+	}
+	if autoscaling.Enabled {
+		return nil
+	}
+	return &replicas
+}
@@ -0,0 +1,141 @@
+package utils
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	"apolloconfig.com/apollo-operator/pkg/utils/naming"
+	"context"
+	"fmt"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CertificateGVK is the kind of the certificates of cert-manager, they are handled as unstructured objects
+// so that the operator runs without cert-manager.
+var CertificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+
+const (
+	certManagerGroup   = "cert-manager.io"
+	issuerKind         = "Issuer"
+	clusterIssuerKind  = "ClusterIssuer"
+	certificateReady   = "Ready"
+	certManagerMissing = "cert-manager is not installed"
+)
+
+// CertificateName returns the name of the certificate of the ingress named name, and of the secret holding it.
+func CertificateName(name string) string {
+	return naming.DNSName(naming.Truncate("%s-tls", 63, name))
+}
+
+// IngressTLS returns the TLS of the ingress named name, a certificate requested from cert-manager covers every host.
+func IngressTLS(name string, ingress apolloiov1alpha1.Ingress) []networkingv1.IngressTLS {
+	if ingress.TLSIssuerRef == nil {
+		return ingress.TLS
+	}
+	return []networkingv1.IngressTLS{{Hosts: ingress.Hosts, SecretName: CertificateName(name)}}
+}
+
+// IngressAnnotations returns the annotations of an ingress, with the annotations of the ingress-shim of cert-manager
+// when the certificate is requested through them.
+func IngressAnnotations(ingress apolloiov1alpha1.Ingress) map[string]string {
+	ref := ingress.TLSIssuerRef
+	if ref == nil || !ref.UseAnnotations {
+		return ingress.Annotations
+	}
+	annotations := map[string]string{}
+	for k, v := range ingress.Annotations {
+		annotations[k] = v
+	}
+	kind, group := issuerKindAndGroup(ref)
+	switch {
+	case group != certManagerGroup:
+		annotations["cert-manager.io/issuer"] = ref.Name
+		annotations["cert-manager.io/issuer-kind"] = kind
+		annotations["cert-manager.io/issuer-group"] = group
+	case kind == clusterIssuerKind:
+		annotations["cert-manager.io/cluster-issuer"] = ref.Name
+	default:
+		annotations["cert-manager.io/issuer"] = ref.Name
+	}
+	return annotations
+}
+
+// Certificate builds the cert-manager Certificate of the ingress named name, it is nil when the operator does not request one.
+func Certificate(instance client.Object, name string, ingress apolloiov1alpha1.Ingress) *unstructured.Unstructured {
+	ref := ingress.TLSIssuerRef
+	if ref == nil || ref.UseAnnotations {
+		return nil
+	}
+	kind, group := issuerKindAndGroup(ref)
+	dnsNames := make([]interface{}, 0, len(ingress.Hosts))
+	for _, host := range ingress.Hosts {
+		dnsNames = append(dnsNames, host)
+	}
+
+	certificate := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"secretName": CertificateName(name),
+			"dnsNames":   dnsNames,
+			"issuerRef": map[string]interface{}{
+				"name":  ref.Name,
+				"kind":  kind,
+				"group": group,
+			},
+		},
+	}}
+	certificate.SetGroupVersionKind(CertificateGVK)
+	certificate.SetName(CertificateName(name))
+	certificate.SetNamespace(instance.GetNamespace())
+	certificate.SetLabels(Labels(instance, CertificateName(name), []string{}))
+	return certificate
+}
+
+// CertificateStatus reports the readiness of the certificate of the ingress named name, it is nil when no certificate is requested.
+// The certificate created by the ingress-shim of cert-manager is named like the secret, too.
+func CertificateStatus(ctx context.Context, c client.Client, namespace, name string, ingress apolloiov1alpha1.Ingress) (*apolloiov1alpha1.CertificateStatus, error) {
+	if ingress.TLSIssuerRef == nil {
+		return nil, nil
+	}
+	status := &apolloiov1alpha1.CertificateStatus{Name: CertificateName(name)}
+
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(CertificateGVK)
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: status.Name}, certificate); err != nil {
+		switch {
+		case meta.IsNoMatchError(err):
+			status.Message = certManagerMissing
+		case k8serrors.IsNotFound(err):
+			status.Message = "the certificate has not been created yet"
+		default:
+			return nil, fmt.Errorf("failed to get certificate %s: %w", status.Name, err)
+		}
+		return status, nil
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(certificate.Object, "status", "conditions")
+	status.Message = "the certificate has not been issued yet"
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != certificateReady {
+			continue
+		}
+		status.Ready = condition["status"] == "True"
+		status.Message, _ = condition["message"].(string)
+	}
+	return status, nil
+}
+
+func issuerKindAndGroup(ref *apolloiov1alpha1.TLSIssuerRef) (string, string) {
+	kind, group := ref.Kind, ref.Group
+	if kind == "" {
+		kind = issuerKind
+	}
+	if group == "" {
+		group = certManagerGroup
+	}
+	return kind, group
+}
@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
+)
+
+// SetConfigHash annotates the pod template with the content hash of the configmaps and secrets it uses. Only the keys
+// selected by the env of the containers are hashed, so that e.g. the password staged by a rotation does not roll the pods,
+// the mounted objects and the objects used through envFrom are hashed entirely. The configmaps are looked up in desired first, so that the hash follows the configmaps rendered in the same reconcile,
+// everything else is read from the cluster. A referenced object that does not exist yet is skipped.
+func SetConfigHash(ctx context.Context, c client.Client, namespace string, template *corev1.PodTemplateSpec, desired []corev1.ConfigMap) error {
+	configMaps, secrets := podTemplateReferences(template)
+
+	var entries []string
+	for name, ref := range configMaps {
+		data, err := configMapData(ctx, c, namespace, name, desired)
+		if err != nil {
+			return err
+		}
+		for key, value := range data {
+			if !ref.uses(key) {
+				continue
+			}
+			entries = append(entries, fmt.Sprintf("configmap/%s/%s=%s", name, key, value))
+		}
+	}
+	for name, ref := range secrets {
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret); err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to get secret %s: %w", name, err)
+		}
+		for key, value := range secret.Data {
+			if !ref.uses(key) {
+				continue
+			}
+			entries = append(entries, fmt.Sprintf("secret/%s/%s=%s", name, key, value))
+		}
+	}
+	sort.Strings(entries)
+
+	hash := sha256.New()
+	for _, entry := range entries {
+		hash.Write([]byte(entry))
+		hash.Write([]byte{0})
+	}
+
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[ConfigHashAnnotation] = hex.EncodeToString(hash.Sum(nil))
+	return nil
+}
+
+func configMapData(ctx context.Context, c client.Client, namespace, name string, desired []corev1.ConfigMap) (map[string]string, error) {
+	for _, cm := range desired {
+		if cm.Name == name {
+			return cm.Data, nil
+		}
+	}
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, cm); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get configmap %s: %w", name, err)
+	}
+	return cm.Data, nil
+}
+
+// reference is the part of a configmap or a secret used by a pod template.
+type reference struct {
+	// all is set when the whole object is used, i.e. it is mounted or used through envFrom.
+	all bool
+	// keys are selected by the env of the containers.
+	keys map[string]bool
+}
+
+func (r *reference) uses(key string) bool {
+	return r.all || r.keys[key]
+}
+
+// references collects the configmaps or the secrets used by a pod template by name.
+type references map[string]*reference
+
+func (r references) get(name string) *reference {
+	if r[name] == nil {
+		r[name] = &reference{keys: map[string]bool{}}
+	}
+	return r[name]
+}
+
+func (r references) addAll(name string) {
+	r.get(name).all = true
+}
+
+func (r references) addKey(name, key string) {
+	r.get(name).keys[key] = true
+}
+
+// podTemplateReferences returns the configmaps and secrets mounted or referenced by the pod template.
+func podTemplateReferences(template *corev1.PodTemplateSpec) (configMaps, secrets references) {
+	configMaps, secrets = references{}, references{}
+	for _, volume := range template.Spec.Volumes {
+		if volume.ConfigMap != nil {
+			configMaps.addAll(volume.ConfigMap.Name)
+		}
+		if volume.Secret != nil {
+			secrets.addAll(volume.Secret.SecretName)
+		}
+		if volume.Projected != nil {
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					configMaps.addAll(source.ConfigMap.Name)
+				}
+				if source.Secret != nil {
+					secrets.addAll(source.Secret.Name)
+				}
+			}
+		}
+	}
+	containers := append(append([]corev1.Container{}, template.Spec.InitContainers...), template.Spec.Containers...)
+	for _, container := range containers {
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil {
+				configMaps.addKey(ref.Name, ref.Key)
+			}
+			if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+				secrets.addKey(ref.Name, ref.Key)
+			}
+		}
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.ConfigMapRef != nil {
+				configMaps.addAll(envFrom.ConfigMapRef.Name)
+			}
+			if envFrom.SecretRef != nil {
+				secrets.addAll(envFrom.SecretRef.Name)
+			}
+		}
+	}
+	return configMaps, secrets
+}
@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSetConfigHash(t *testing.T) {
+	secret := func(data map[string]string) *corev1.Secret {
+		s := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"}, Data: map[string][]byte{}}
+		for k, v := range data {
+			s.Data[k] = []byte(v)
+		}
+		return s
+	}
+	keyRef := corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+		Env: []corev1.EnvVar{{
+			Name:      "DB_PASSWORD",
+			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: SecretKeyRef(nil, "db", SecretPasswordKey)},
+		}},
+	}}}}
+	envFrom := corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+		EnvFrom: []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "db"}}}},
+	}}}}
+	mounted := corev1.PodTemplateSpec{Spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+		Name:         "db",
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "db"}},
+	}}}}
+
+	current := map[string]string{SecretPasswordKey: "a"}
+	tests := []struct {
+		name       string
+		template   corev1.PodTemplateSpec
+		changed    map[string]string
+		wantRolled bool
+	}{
+		{
+			name:     "a staged password does not change the hash of a key reference",
+			template: keyRef,
+			changed:  map[string]string{SecretPasswordKey: "a", SecretPendingPasswordKey: "b", SecretPreviousPasswordKey: "c"},
+		},
+		{
+			name:       "the referenced key changes the hash",
+			template:   keyRef,
+			changed:    map[string]string{SecretPasswordKey: "b", SecretPreviousPasswordKey: "a"},
+			wantRolled: true,
+		},
+		{
+			name:       "any key changes the hash with envFrom",
+			template:   envFrom,
+			changed:    map[string]string{SecretPasswordKey: "a", SecretPendingPasswordKey: "b"},
+			wantRolled: true,
+		},
+		{
+			name:       "any key changes the hash of a mounted secret",
+			template:   mounted,
+			changed:    map[string]string{SecretPasswordKey: "a", SecretPendingPasswordKey: "b"},
+			wantRolled: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash := func(data map[string]string) string {
+				c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret(data)).Build()
+				template := tt.template.DeepCopy()
+				if err := SetConfigHash(context.Background(), c, "default", template, nil); err != nil {
+					t.Fatalf("SetConfigHash() error = %v", err)
+				}
+				return template.Annotations[ConfigHashAnnotation]
+			}
+
+			if rolled := hash(current) != hash(tt.changed); rolled != tt.wantRolled {
+				t.Errorf("hash changed = %v, want %v", rolled, tt.wantRolled)
+			}
+		})
+	}
+}
@@ -5,3 +5,44 @@ const (
 	HeadlessLabel  = "apolloconfig.com/apollo-headless-service"
 	HeadlessExists = "Exists"
 )
+
+// keys of the database credentials stored in operator managed secrets.
+const (
+	SecretUsernameKey = "username"
+	SecretPasswordKey = "password"
+	// SecretPendingPasswordKey stages the password of a rotation until the rotation job changed it in the database.
+	SecretPendingPasswordKey = "pending-password"
+	// SecretPreviousPasswordKey keeps the password replaced by the last rotation.
+	SecretPreviousPasswordKey = "previous-password"
+)
+
+// DBPasswordRotationAnnotation triggers a rotation of the generated database password whenever its value changes.
+const DBPasswordRotationAnnotation = "apolloconfig.com/rotate-db-password"
+
+// DBPasswordRotatedAnnotation records on the secret the rotation whose password is in use, the pods are rolled when it changes.
+const DBPasswordRotatedAnnotation = "apolloconfig.com/rotated-db-password"
+
+// ConfigHashAnnotation holds the content hash of the configmaps and secrets used by a pod template,
+// a change of the content rolls the pods.
+const ConfigHashAnnotation = "apolloconfig.com/config-hash"
+
+// SchemaVersionAnnotation records the schema version a schema job applies.
+const SchemaVersionAnnotation = "apolloconfig.com/schema-version"
+
+// RestoreAnnotation is set on an apollo resource while an ApolloRestore restores its databases,
+// the Deployments of the resource are kept scaled to zero until it is removed.
+const RestoreAnnotation = "apolloconfig.com/restore"
+
+// BackupScheduleLabel records the ApolloBackupSchedule which created a backup.
+const BackupScheduleLabel = "apolloconfig.com/backup-schedule"
+
+// Finalizer is added to every Apollo kind, the operator cleans up the data and the resources which cannot be owned
+// by the instance before it removes the finalizer.
+const Finalizer = "apolloconfig.com/finalizer"
+
+// FieldManager is the field manager of the objects server-side applied by the operator, it owns only the fields the operator renders.
+const FieldManager = "apollo-operator"
+
+// DatabaseClientLabel marks the pods of the jobs connecting to a database, e.g. the backups,
+// the NetworkPolicy of the all-in-one database accepts them.
+const DatabaseClientLabel = "apolloconfig.com/database-client"
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DatasourceProperties builds the spring properties connecting apollo to the database, the url format and the driver depend on the database type.
+func DatasourceProperties(dbType apolloiov1alpha1.DatabaseType, host string, port int32, dbName, properties string) []string {
+	switch dbType {
+	case apolloiov1alpha1.DatabaseTypePostgreSQL:
+		url := fmt.Sprintf("jdbc:postgresql://%s:%d/%s", host, port, dbName)
+		if properties != "" {
+			url += "?" + properties
+		}
+		return []string{
+			"spring.datasource.url = " + url,
+			"spring.datasource.driver-class-name = org.postgresql.Driver",
+			"spring.jpa.database-platform = org.hibernate.dialect.PostgreSQLDialect",
+		}
+	default:
+		return []string{
+			fmt.Sprintf("spring.datasource.url = jdbc:mysql://%s:%d/%s?%s", host, port, dbName, properties),
+		}
+	}
+}
+
+// DatasourceEnv builds the environment variables that hand the database credentials to spring,
+// so that they are read from secrets instead of being rendered into a configmap.
+func DatasourceEnv(username, password *corev1.SecretKeySelector) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{
+			Name:      "SPRING_DATASOURCE_USERNAME",
+			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: username},
+		},
+		{
+			Name:      "SPRING_DATASOURCE_PASSWORD",
+			ValueFrom: &corev1.EnvVarSource{SecretKeyRef: password},
+		},
+	}
+}
+
+// SecretKeyRef returns ref if it is set, otherwise a selector for key in the operator managed secret.
+func SecretKeyRef(ref *corev1.SecretKeySelector, secretName, key string) *corev1.SecretKeySelector {
+	if ref != nil {
+		return ref
+	}
+	return &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+		Key:                  key,
+	}
+}
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PodDisruptionBudget builds the PodDisruptionBudget of the pods of the deployment named name, selected by selector.
+// It is nil when the budget is disabled, or not enabled and the deployment cannot run more than one replica.
+func PodDisruptionBudget(instance client.Object, name string, selector map[string]string, replicas int32,
+	autoscaling apolloiov1alpha1.Autoscaling, pdb apolloiov1alpha1.PodDisruptionBudget) *policyv1.PodDisruptionBudget {
+	maxReplicas := replicas
+	if autoscaling.Enabled {
+		maxReplicas = autoscaling.MaxReplicas
+	}
+	if pdb.Enabled == nil && maxReplicas <= 1 || pdb.Enabled != nil && !*pdb.Enabled {
+		return nil
+	}
+
+	spec := policyv1.PodDisruptionBudgetSpec{
+		Selector:       &metav1.LabelSelector{MatchLabels: selector},
+		MinAvailable:   pdb.MinAvailable,
+		MaxUnavailable: pdb.MaxUnavailable,
+	}
+	if spec.MinAvailable == nil && spec.MaxUnavailable == nil {
+		// NOTE 用maxUnavailable而不是minAvailable，单副本时也不会阻塞节点驱逐
+		maxUnavailable := intstr.FromInt(1)
+		spec.MaxUnavailable = &maxUnavailable
+	}
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.GetNamespace(),
+			Labels:    Labels(instance, name, []string{}),
+		},
+		Spec: spec,
+	}
+}
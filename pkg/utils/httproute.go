@@ -0,0 +1,75 @@
+package utils
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// HTTPRouteGVK is the kind of the HTTPRoutes of the Gateway API, they are handled as unstructured objects
+// so that the operator runs without the Gateway API CRDs.
+var HTTPRouteGVK = schema.GroupVersionKind{Group: gatewayGroup, Version: "v1", Kind: "HTTPRoute"}
+
+const gatewayGroup = "gateway.networking.k8s.io"
+
+// HTTPRoute builds the HTTPRoute named name routing the hosts of ingress under contextPath to port of service,
+// it is nil when the component is not exposed through the Gateway API.
+// TLS is terminated by the listeners of the parent Gateways, so the tls of the ingress is not used here.
+func HTTPRoute(instance client.Object, name string, exposure apolloiov1alpha1.Exposure, ingress apolloiov1alpha1.Ingress,
+	contextPath, service string, port int32) *unstructured.Unstructured {
+	if exposure.Type != apolloiov1alpha1.ExposureTypeGatewayAPI {
+		return nil
+	}
+
+	parentRefs := make([]interface{}, 0, len(exposure.GatewayAPI.ParentRefs))
+	for _, ref := range exposure.GatewayAPI.ParentRefs {
+		parentRef := map[string]interface{}{
+			"group": gatewayGroup,
+			"kind":  "Gateway",
+			"name":  ref.Name,
+		}
+		if ref.Namespace != "" {
+			parentRef["namespace"] = ref.Namespace
+		}
+		if ref.SectionName != "" {
+			parentRef["sectionName"] = ref.SectionName
+		}
+		parentRefs = append(parentRefs, parentRef)
+	}
+
+	// NOTE 与ingress不同，HTTPRoute只转发contextPath下的请求
+	path := contextPath
+	if path == "" {
+		path = "/"
+	}
+	spec := map[string]interface{}{
+		"parentRefs": parentRefs,
+		"rules": []interface{}{
+			map[string]interface{}{
+				"matches": []interface{}{
+					map[string]interface{}{
+						"path": map[string]interface{}{"type": "PathPrefix", "value": path},
+					},
+				},
+				"backendRefs": []interface{}{
+					map[string]interface{}{"name": service, "port": int64(port)},
+				},
+			},
+		},
+	}
+	if len(ingress.Hosts) > 0 {
+		hostnames := make([]interface{}, 0, len(ingress.Hosts))
+		for _, host := range ingress.Hosts {
+			hostnames = append(hostnames, host)
+		}
+		spec["hostnames"] = hostnames
+	}
+
+	route := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	route.SetGroupVersionKind(HTTPRouteGVK)
+	route.SetName(name)
+	route.SetNamespace(instance.GetNamespace())
+	route.SetLabels(Labels(instance, name, []string{}))
+	return route
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"apolloconfig.com/apollo-operator/pkg/utils/naming"
+	"reflect"
 	"regexp"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"strings"
@@ -48,10 +49,18 @@ func SelectorLabels(instance client.Object) map[string]string {
 		"app.kubernetes.io/managed-by": "apollo-operator",
 		"app.kubernetes.io/instance":   naming.Truncate("%s.%s", 63, instance.GetNamespace(), instance.GetName()),
 		"app.kubernetes.io/part-of":    "apollo-operator",
-		"app.kubernetes.io/component":  strings.ToLower(instance.GetObjectKind().GroupVersionKind().Kind), // eg. apolloportal
+		"app.kubernetes.io/component":  strings.ToLower(kind(instance)), // eg. apolloportal
 	}
 }
 
+// kind returns the kind of instance, the GVK is cleared when a typed client decodes a response, e.g. after a status patch.
+func kind(instance client.Object) string {
+	if kind := instance.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+		return kind
+	}
+	return reflect.TypeOf(instance).Elem().Name()
+}
+
 func SelectorLabelsWithCustom(instance client.Object, custom map[string]string) map[string]string {
 	commonLabels := SelectorLabels(instance)
 	return MergeTwoMap(commonLabels, custom)
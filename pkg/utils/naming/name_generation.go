@@ -44,6 +44,21 @@ func ConfigDBService(obj client.Object) string {
 	return DNSName(Truncate("%s-configdb", 63, obj.GetName()))
 }
 
+// ConfigDBSecret builds the name for the config db credentials secret used in the apollo-operator.
+func ConfigDBSecret(obj client.Object) string {
+	return DNSName(Truncate("%s-configdb-secret", 63, obj.GetName()))
+}
+
+// ConfigDBSchemaConfigMap builds the name for the config db schema scripts configmap used in the apollo-operator.
+func ConfigDBSchemaConfigMap(obj client.Object) string {
+	return DNSName(Truncate("%s-configdb-schema-scripts", 63, obj.GetName()))
+}
+
+// ConfigDBSchemaJob builds the name for the config db schema job used in the apollo-operator.
+func ConfigDBSchemaJob(obj client.Object) string {
+	return DNSName(Truncate("%s-configdb-schema", 63, obj.GetName()))
+}
+
 // AdminIngress builds the name for the admin ingress used in the apollo-operator.
 func AdminIngress(obj client.Object) string {
 	return DNSName(Truncate("%s-admin-ingress", 63, obj.GetName()))
@@ -54,6 +69,16 @@ func ConfigIngress(obj client.Object) string {
 	return DNSName(Truncate("%s-config-ingress", 63, obj.GetName()))
 }
 
+// AdminRoute builds the name for the admin route used in the apollo-operator.
+func AdminRoute(obj client.Object) string {
+	return DNSName(Truncate("%s-admin-route", 63, obj.GetName()))
+}
+
+// ConfigRoute builds the name for the config route used in the apollo-operator.
+func ConfigRoute(obj client.Object) string {
+	return DNSName(Truncate("%s-config-route", 63, obj.GetName()))
+}
+
 /* Apollo Portal */
 
 // ConfigMap builds the name for the portal config map used in the apollo-operator.
@@ -71,6 +96,21 @@ func PortalDBService(obj client.Object) string {
 	return DNSName(Truncate("%s-portaldb", 63, obj.GetName()))
 }
 
+// PortalDBSecret builds the name for the portal db credentials secret used in the apollo-operator.
+func PortalDBSecret(obj client.Object) string {
+	return DNSName(Truncate("%s-portaldb-secret", 63, obj.GetName()))
+}
+
+// PortalDBSchemaConfigMap builds the name for the portal db schema scripts configmap used in the apollo-operator.
+func PortalDBSchemaConfigMap(obj client.Object) string {
+	return DNSName(Truncate("%s-portaldb-schema-scripts", 63, obj.GetName()))
+}
+
+// PortalDBSchemaJob builds the name for the portal db schema job used in the apollo-operator.
+func PortalDBSchemaJob(obj client.Object) string {
+	return DNSName(Truncate("%s-portaldb-schema", 63, obj.GetName()))
+}
+
 // PortalService builds the name for the portal service used in the apollo-operator.
 func PortalService(obj client.Object) string {
 	return DNSName(Truncate("%s-portal", 63, obj.GetName()))
@@ -81,6 +121,11 @@ func PortalIngress(obj client.Object) string {
 	return DNSName(Truncate("%s-portal-ingress", 63, obj.GetName()))
 }
 
+// PortalRoute builds the name for the portal route used in the apollo-operator.
+func PortalRoute(obj client.Object) string {
+	return DNSName(Truncate("%s-portal-route", 63, obj.GetName()))
+}
+
 /* Apollo all in one */
 
 // AllInOneAdminConfigMap builds the name for the admin configmap used in the apollo-operator.
@@ -153,11 +198,31 @@ func AllInOneStatefulSet(obj client.Object) string {
 	return DNSName(Truncate("%s-statefulset-allinone", 63, obj.GetName()))
 }
 
+// AllInOneDBDeployment builds the name for the apollo allinone embedded database deployment used in the apollo-operator.
+func AllInOneDBDeployment(obj client.Object) string {
+	return DNSName(Truncate("%s-db-deployment-allinone", 63, obj.GetName()))
+}
+
 // AllInOneDBService builds the name for the allinone db service used in the apollo-operator.
 func AllInOneDBService(obj client.Object) string {
 	return DNSName(Truncate("%s-db-allinone", 63, obj.GetName()))
 }
 
+// AllInOneDBSecret builds the name for the allinone db root password secret used in the apollo-operator.
+func AllInOneDBSecret(obj client.Object) string {
+	return DNSName(Truncate("%s-db-secret-allinone", 63, obj.GetName()))
+}
+
+// AllInOneDBConfigMap builds the name for the apollo allinone database my.cnf configmap used in the apollo-operator.
+func AllInOneDBConfigMap(obj client.Object) string {
+	return DNSName(Truncate("%s-db-configmap-allinone", 63, obj.GetName()))
+}
+
+// AllInOneDBRotateJob builds the name for the allinone db password rotation job used in the apollo-operator.
+func AllInOneDBRotateJob(obj client.Object) string {
+	return DNSName(Truncate("%s-db-rotate-allinone", 63, obj.GetName()))
+}
+
 // AllInOnePVC builds the name for the apollo allinone pvc used in the apollo-operator.
 func AllInOnePVC(obj client.Object) string {
 	return DNSName(Truncate("%s-apolloDB-allinone", 63, obj.GetName()))
@@ -173,6 +238,11 @@ func AllInOneSqlScriptConfigmap(_ client.Object) string {
 	return "mysql-initdb-config" // NOTE 包含初始化sql语句的configmap名字
 }
 
+// AllInOnePostgreSQLScriptConfigmap builds the name for the apollo allinone postgresql sql script configmap used in the apollo-operator.
+func AllInOnePostgreSQLScriptConfigmap(_ client.Object) string {
+	return "postgresql-initdb-config" // NOTE 包含初始化sql语句的configmap名字，见 sql/configmap_postgresql_sql.yaml
+}
+
 /* Public name generation  */
 
 // HeadlessService builds the name for the headless service used in the apollo-operator.
@@ -190,11 +260,61 @@ func ServiceAccount(obj client.Object) string {
 	return DNSName(Truncate("%s-serviceaccount", 63, obj.GetName()))
 }
 
+// ConfigServiceAccount builds the name for the config service account used in the apollo-operator.
+func ConfigServiceAccount(obj client.Object) string {
+	return DNSName(Truncate("%s-config-serviceaccount", 63, obj.GetName()))
+}
+
+// AdminServiceAccount builds the name for the admin service account used in the apollo-operator.
+func AdminServiceAccount(obj client.Object) string {
+	return DNSName(Truncate("%s-admin-serviceaccount", 63, obj.GetName()))
+}
+
+// PortalServiceAccount builds the name for the portal service account used in the apollo-operator.
+func PortalServiceAccount(obj client.Object) string {
+	return DNSName(Truncate("%s-portal-serviceaccount", 63, obj.GetName()))
+}
+
+// DBServiceAccount builds the name for the database service account used in the apollo-operator.
+func DBServiceAccount(obj client.Object) string {
+	return DNSName(Truncate("%s-db-serviceaccount", 63, obj.GetName()))
+}
+
 // Apollo builds the apollo resource name used in the apollo-operator.
 func Apollo(obj client.Object) string {
 	return DNSName(Truncate("%s", 63, obj.GetName()))
 }
 
+// BackupPVC builds the name for the pvc holding the database dumps of a backup or a backup schedule.
+func BackupPVC(obj client.Object) string {
+	return DNSName(Truncate("%s-backup-pvc", 63, obj.GetName()))
+}
+
+// BackupJob builds the name for the job dumping the databases of a backup, the job of a scheduled backup is named by its cronjob.
+func BackupJob(obj client.Object) string {
+	return DNSName(Truncate("%s", 63, obj.GetName()))
+}
+
+// BackupCronJob builds the name for the cronjob of a backup schedule, the cronjob controller appends 11 characters to the job names.
+func BackupCronJob(obj client.Object) string {
+	return DNSName(Truncate("%s-backup", 52, obj.GetName()))
+}
+
+// SnapshotBackup builds the name for the ApolloBackup taken before an instance is deleted, the uid keeps the snapshots
+// of instances deleted and created again with the same name apart.
+func SnapshotBackup(obj client.Object) string {
+	uid := string(obj.GetUID())
+	if len(uid) > 8 {
+		uid = uid[:8]
+	}
+	return DNSName(Truncate("%s-snapshot-%s", 63, obj.GetName(), uid))
+}
+
+// RestoreJob builds the name for the job restoring the database dumps of a restore.
+func RestoreJob(obj client.Object) string {
+	return DNSName(Truncate("%s-restore", 63, obj.GetName()))
+}
+
 // Container returns the name to use for the container in the pod.
 func Container() string {
 	return "apollo-container"
@@ -0,0 +1,97 @@
+package utils
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NetworkPolicy builds a NetworkPolicy allowing the traffic from the peers to port of the pods selected by podSelector,
+// any other ingress traffic of the pods is denied. Every source is allowed to reach port when from is empty.
+func NetworkPolicy(instance client.Object, name string, podSelector map[string]string, port int32, from []networkingv1.NetworkPolicyPeer) networkingv1.NetworkPolicy {
+	protocol := corev1.ProtocolTCP
+	networkPort := intstr.FromInt(int(port))
+	return networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.GetNamespace(),
+			Labels:    Labels(instance, name, []string{}),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: podSelector},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					Ports: []networkingv1.NetworkPolicyPort{{Protocol: &protocol, Port: &networkPort}},
+					From:  from,
+				},
+			},
+		},
+	}
+}
+
+// ConfigServicePeers returns the peers allowed to call the config service, nil allows every client.
+func ConfigServicePeers(policy apolloiov1alpha1.NetworkPolicy) []networkingv1.NetworkPolicyPeer {
+	if len(policy.ClientNamespaceSelectors) == 0 {
+		return nil
+	}
+	// NOTE 同一个namespace中的pod都可以访问，admin和portal通过config service的meta server发现服务
+	peers := []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}}
+	peers = append(peers, namespacePeers(policy.ClientNamespaceSelectors, nil)...)
+	return append(peers, namespacePeers(policy.PortalNamespaceSelectors, portalPods())...)
+}
+
+// AdminServicePeers returns the peers allowed to call the admin service, the ApolloPortals of the namespace of the instance
+// and of the portal namespaces, and the pods selected by local, e.g. the portal of the all-in-one kind.
+func AdminServicePeers(policy apolloiov1alpha1.NetworkPolicy, local ...map[string]string) []networkingv1.NetworkPolicyPeer {
+	peers := []networkingv1.NetworkPolicyPeer{{PodSelector: portalPods()}}
+	for _, selector := range local {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{MatchLabels: selector}})
+	}
+	return append(peers, namespacePeers(policy.PortalNamespaceSelectors, portalPods())...)
+}
+
+// PortalPeers returns the peers allowed to call an ApolloPortal, nil allows every client.
+func PortalPeers(policy apolloiov1alpha1.NetworkPolicy) []networkingv1.NetworkPolicyPeer {
+	if len(policy.ClientNamespaceSelectors) == 0 {
+		return nil
+	}
+	peers := []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}}
+	return append(peers, namespacePeers(policy.ClientNamespaceSelectors, nil)...)
+}
+
+// DatabasePeers returns the peers allowed to connect to a database deployed by the operator: the pods selected by local,
+// the jobs labeled with DatabaseClientLabel and the operator, which dials the database to report whether it is reachable.
+func DatabasePeers(local ...map[string]string) []networkingv1.NetworkPolicyPeer {
+	var peers []networkingv1.NetworkPolicyPeer
+	for _, selector := range local {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{MatchLabels: selector}})
+	}
+	// NOTE 和config/manager中operator的namespace和pod的标签保持一致
+	operator := &metav1.LabelSelector{MatchLabels: map[string]string{"control-plane": "controller-manager"}}
+	return append(peers,
+		networkingv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{DatabaseClientLabel: "true"}}},
+		networkingv1.NetworkPolicyPeer{NamespaceSelector: operator, PodSelector: operator},
+	)
+}
+
+// portalPods selects the pods of the ApolloPortals.
+func portalPods() *metav1.LabelSelector {
+	return &metav1.LabelSelector{MatchLabels: map[string]string{
+		"app.kubernetes.io/managed-by": "apollo-operator",
+		"app.kubernetes.io/component":  "apolloportal",
+	}}
+}
+
+// namespacePeers returns a peer selecting the pods selected by podSelector in the namespaces of every selector,
+// every pod of the namespaces is selected when podSelector is nil.
+func namespacePeers(selectors []metav1.LabelSelector, podSelector *metav1.LabelSelector) []networkingv1.NetworkPolicyPeer {
+	peers := make([]networkingv1.NetworkPolicyPeer, 0, len(selectors))
+	for i := range selectors {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{NamespaceSelector: &selectors[i], PodSelector: podSelector})
+	}
+	return peers
+}
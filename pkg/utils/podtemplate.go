@@ -0,0 +1,115 @@
+package utils
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// The users of the restricted security context when the overrides have none, the images start as root by default.
+const (
+	// ApolloUserID runs the config, admin and portal services.
+	ApolloUserID int64 = 1000
+	// DatabaseUserID is the mysql and postgres user of their official images.
+	DatabaseUserID int64 = 999
+)
+
+// logsVolume is the emptyDir holding the logs of the apollo services, a non-root user cannot write the /opt/logs of the images.
+const logsVolume = "logs"
+
+// ApplyPodTemplateOverrides applies the overrides of a component to its pod template, with the restricted security
+// defaults. userID runs the pods when the overrides have no pod securityContext.
+func ApplyPodTemplateOverrides(template *corev1.PodTemplateSpec, overrides apolloiov1alpha1.PodTemplateOverrides, userID int64) {
+	// NOTE 选择器标签和operator设置的注解优先
+	template.Labels = mergeMaps(overrides.PodLabels, template.Labels)
+	template.Annotations = mergeMaps(overrides.PodAnnotations, template.Annotations)
+
+	spec := &template.Spec
+	spec.SecurityContext = podSecurityContext(overrides.SecurityContext, userID)
+	for i := range spec.InitContainers {
+		spec.InitContainers[i].SecurityContext = containerSecurityContext(overrides.ContainerSecurityContext)
+	}
+	for i := range spec.Containers {
+		spec.Containers[i].SecurityContext = containerSecurityContext(overrides.ContainerSecurityContext)
+	}
+	spec.PriorityClassName = overrides.PriorityClassName
+	spec.TopologySpreadConstraints = overrides.TopologySpreadConstraints
+	spec.RuntimeClassName = overrides.RuntimeClassName
+	spec.HostAliases = overrides.HostAliases
+	spec.DNSConfig = overrides.DNSConfig
+}
+
+// ApplyRestrictedSecurity sets the restricted security defaults on the pods built by the operator without overrides,
+// such as the pods of the schema, rotation, backup and restore jobs. userID runs the pods.
+func ApplyRestrictedSecurity(spec *corev1.PodSpec, userID int64) {
+	spec.SecurityContext = podSecurityContext(nil, userID)
+	for i := range spec.InitContainers {
+		spec.InitContainers[i].SecurityContext = containerSecurityContext(nil)
+	}
+	for i := range spec.Containers {
+		spec.Containers[i].SecurityContext = containerSecurityContext(nil)
+	}
+}
+
+// WritableLogs mounts an emptyDir on the log directory of the apollo services in every container of template.
+func WritableLogs(template *corev1.PodTemplateSpec) {
+	template.Spec.Volumes = append(template.Spec.Volumes, corev1.Volume{
+		Name:         logsVolume,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	})
+	for i := range template.Spec.Containers {
+		template.Spec.Containers[i].VolumeMounts = append(template.Spec.Containers[i].VolumeMounts, corev1.VolumeMount{
+			Name:      logsVolume,
+			MountPath: "/opt/logs",
+		})
+	}
+}
+
+func podSecurityContext(securityContext *corev1.PodSecurityContext, userID int64) *corev1.PodSecurityContext {
+	var result *corev1.PodSecurityContext
+	if securityContext == nil {
+		result = &corev1.PodSecurityContext{
+			RunAsUser:  &userID,
+			RunAsGroup: &userID,
+			FSGroup:    &userID,
+		}
+	} else {
+		result = securityContext.DeepCopy()
+	}
+	if result.RunAsNonRoot == nil {
+		runAsNonRoot := true
+		result.RunAsNonRoot = &runAsNonRoot
+	}
+	if result.SeccompProfile == nil {
+		result.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	}
+	return result
+}
+
+func containerSecurityContext(securityContext *corev1.SecurityContext) *corev1.SecurityContext {
+	result := &corev1.SecurityContext{}
+	if securityContext != nil {
+		result = securityContext.DeepCopy()
+	}
+	if result.AllowPrivilegeEscalation == nil {
+		allowPrivilegeEscalation := false
+		result.AllowPrivilegeEscalation = &allowPrivilegeEscalation
+	}
+	if result.Capabilities == nil {
+		result.Capabilities = &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}}
+	}
+	return result
+}
+
+// mergeMaps returns the entries of every map, the later maps win. It is nil when every map is empty.
+func mergeMaps(maps ...map[string]string) map[string]string {
+	var result map[string]string
+	for _, m := range maps {
+		for k, v := range m {
+			if result == nil {
+				result = map[string]string{}
+			}
+			result[k] = v
+		}
+	}
+	return result
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	"apolloconfig.com/apollo-operator/pkg/schema"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// ImagesChanged reports whether the desired deployment runs other images than the existing one.
+func ImagesChanged(desired, existing *appsv1.Deployment) bool {
+	desiredContainers := desired.Spec.Template.Spec.Containers
+	existingContainers := existing.Spec.Template.Spec.Containers
+	if len(desiredContainers) != len(existingContainers) {
+		return true
+	}
+	for i := range desiredContainers {
+		if desiredContainers[i].Image != existingContainers[i].Image {
+			return true
+		}
+	}
+	return false
+}
+
+// RolloutPending returns the name of the first deployment which is not rolled out yet, or "" if all of them are.
+// The deployments are upgraded in order, an image change waits until every deployment before it is available.
+func RolloutPending(deployments []*appsv1.Deployment) string {
+	for _, deployment := range deployments {
+		if !deploymentAvailable(deployment) {
+			return deployment.Name
+		}
+	}
+	return ""
+}
+
+// SetVersionStatus records target as the version being rolled out, it becomes the current version once every deployment
+// runs images of that version and is available. A nil entry in deployments means the deployment has not been created yet.
+func SetVersionStatus(status *apolloiov1alpha1.VersionStatus, target string, deployments []*appsv1.Deployment) {
+	status.Target = target
+	for _, deployment := range deployments {
+		if deployment == nil || !deploymentAvailable(deployment) {
+			return
+		}
+		for _, container := range deployment.Spec.Template.Spec.Containers {
+			if schema.ImageVersion(container.Image) != target {
+				return
+			}
+		}
+	}
+	status.Current = target
+}
@@ -0,0 +1,106 @@
+package utils
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	"apolloconfig.com/apollo-operator/pkg/utils/naming"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RouteGVK is the kind of the Routes of OpenShift, they are handled as unstructured objects
+// so that the operator runs on the clusters without the route.openshift.io API.
+var RouteGVK = schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "Route"}
+
+// Routes builds the Routes named after name routing the hosts of ingress under contextPath to the http port of service,
+// they are empty when the component is not exposed through Routes. The name of the ingress, ingressName, names the
+// certificate requested by tlsIssuerRef.
+func Routes(instance client.Object, name string, exposure apolloiov1alpha1.Exposure, ingress apolloiov1alpha1.Ingress,
+	ingressName, contextPath, service string) []unstructured.Unstructured {
+	if exposure.Type != apolloiov1alpha1.ExposureTypeRoute {
+		return nil
+	}
+
+	// NOTE 每个route只有一个host，没有host时由router生成
+	hosts := ingress.Hosts
+	if len(hosts) == 0 {
+		hosts = []string{""}
+	}
+	tls := IngressTLS(ingressName, ingress)
+
+	routes := make([]unstructured.Unstructured, 0, len(hosts))
+	for i, host := range hosts {
+		routeName := name
+		if i > 0 {
+			routeName = naming.DNSName(naming.Truncate("%s-%d", 63, name, i))
+		}
+
+		spec := map[string]interface{}{
+			"to": map[string]interface{}{
+				"kind":   "Service",
+				"name":   service,
+				"weight": int64(100),
+			},
+			"port": map[string]interface{}{"targetPort": "http"},
+		}
+		if host != "" {
+			spec["host"] = host
+		}
+		// NOTE passthrough的route不能按path转发
+		if contextPath != "" && exposure.Route.Termination != apolloiov1alpha1.RouteTerminationPassthrough {
+			spec["path"] = contextPath
+		}
+		if routeTLS := routeTLS(exposure.Route, host, tls); routeTLS != nil {
+			spec["tls"] = routeTLS
+		}
+
+		route := unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+		route.SetGroupVersionKind(RouteGVK)
+		route.SetName(routeName)
+		route.SetNamespace(instance.GetNamespace())
+		route.SetLabels(Labels(instance, routeName, []string{}))
+		if len(exposure.Route.Annotations) > 0 {
+			route.SetAnnotations(exposure.Route.Annotations)
+		}
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+// routeTLS returns the tls of the Route of host, the secret of the ingress tls covering host is used as external certificate.
+func routeTLS(route apolloiov1alpha1.RouteExposure, host string, tls []networkingv1.IngressTLS) map[string]interface{} {
+	if route.Termination == "" {
+		return nil
+	}
+	result := map[string]interface{}{"termination": string(route.Termination)}
+	if route.InsecureEdgeTerminationPolicy != "" {
+		result["insecureEdgeTerminationPolicy"] = route.InsecureEdgeTerminationPolicy
+	}
+	if route.DestinationCACertificate != "" {
+		result["destinationCACertificate"] = route.DestinationCACertificate
+	}
+	if route.Termination == apolloiov1alpha1.RouteTerminationPassthrough {
+		return result
+	}
+	for _, t := range tls {
+		if t.SecretName != "" && coversHost(t, host) {
+			result["externalCertificate"] = map[string]interface{}{"name": t.SecretName}
+			break
+		}
+	}
+	return result
+}
+
+// coversHost reports whether the ingress tls t serves host, a tls without hosts serves every host.
+func coversHost(t networkingv1.IngressTLS, host string) bool {
+	if host == "" || len(t.Hosts) == 0 {
+		return true
+	}
+	for _, h := range t.Hosts {
+		if h == host {
+			return true
+		}
+	}
+	return false
+}
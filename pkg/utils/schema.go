@@ -0,0 +1,54 @@
+package utils
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	"context"
+	"fmt"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SchemaJobCondition inspects the schema job applying version and returns the SchemaReady condition describing it,
+// ready is true once the job succeeded.
+func SchemaJobCondition(ctx context.Context, c client.Client, namespace, name, version string) (condition metav1.Condition, ready bool, err error) {
+	condition = metav1.Condition{
+		Type:    apolloiov1alpha1.ConditionSchemaReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  apolloiov1alpha1.ReasonSchemaPending,
+		Message: fmt.Sprintf("waiting for job %s to apply schema version %s", name, version),
+	}
+
+	job := &batchv1.Job{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, job); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return condition, false, nil
+		}
+		return condition, false, fmt.Errorf("failed to get job %s: %w", name, err)
+	}
+	// NOTE 旧版本的job正在被替换
+	if job.Annotations[SchemaVersionAnnotation] != version {
+		return condition, false, nil
+	}
+
+	for _, jc := range job.Status.Conditions {
+		if jc.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch jc.Type {
+		case batchv1.JobComplete:
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = apolloiov1alpha1.ReasonSchemaApplied
+			condition.Message = fmt.Sprintf("schema version %s is applied", version)
+			return condition, true, nil
+		case batchv1.JobFailed:
+			condition.Reason = apolloiov1alpha1.ReasonSchemaFailed
+			condition.Message = fmt.Sprintf("job %s failed to apply schema version %s: %s", name, version, jc.Message)
+			return condition, false, nil
+		}
+	}
+	return condition, false, nil
+}
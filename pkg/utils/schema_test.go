@@ -0,0 +1,93 @@
+package utils
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	"context"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSchemaJobCondition(t *testing.T) {
+	job := func(version string, conditions ...batchv1.JobCondition) *batchv1.Job {
+		return &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "schema",
+				Namespace:   "default",
+				Annotations: map[string]string{SchemaVersionAnnotation: version},
+			},
+			Status: batchv1.JobStatus{Conditions: conditions},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		job        *batchv1.Job
+		wantStatus metav1.ConditionStatus
+		wantReason string
+		wantReady  bool
+	}{
+		{
+			name:       "pending without the job",
+			wantStatus: metav1.ConditionFalse,
+			wantReason: apolloiov1alpha1.ReasonSchemaPending,
+		},
+		{
+			name:       "pending while the job of another version is replaced",
+			job:        job("1.9.0", batchv1.JobCondition{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}),
+			wantStatus: metav1.ConditionFalse,
+			wantReason: apolloiov1alpha1.ReasonSchemaPending,
+		},
+		{
+			name:       "pending while the job runs",
+			job:        job("2.0.0"),
+			wantStatus: metav1.ConditionFalse,
+			wantReason: apolloiov1alpha1.ReasonSchemaPending,
+		},
+		{
+			name:       "pending with a false condition",
+			job:        job("2.0.0", batchv1.JobCondition{Type: batchv1.JobComplete, Status: corev1.ConditionFalse}),
+			wantStatus: metav1.ConditionFalse,
+			wantReason: apolloiov1alpha1.ReasonSchemaPending,
+		},
+		{
+			name:       "applied when the job completed",
+			job:        job("2.0.0", batchv1.JobCondition{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}),
+			wantStatus: metav1.ConditionTrue,
+			wantReason: apolloiov1alpha1.ReasonSchemaApplied,
+			wantReady:  true,
+		},
+		{
+			name:       "failed when the job failed",
+			job:        job("2.0.0", batchv1.JobCondition{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"}),
+			wantStatus: metav1.ConditionFalse,
+			wantReason: apolloiov1alpha1.ReasonSchemaFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(scheme.Scheme)
+			if tt.job != nil {
+				builder = builder.WithObjects(tt.job)
+			}
+
+			condition, ready, err := SchemaJobCondition(context.Background(), builder.Build(), "default", "schema", "2.0.0")
+			if err != nil {
+				t.Fatalf("SchemaJobCondition() error = %v", err)
+			}
+			if condition.Type != apolloiov1alpha1.ConditionSchemaReady {
+				t.Errorf("type = %s, want %s", condition.Type, apolloiov1alpha1.ConditionSchemaReady)
+			}
+			if condition.Status != tt.wantStatus || condition.Reason != tt.wantReason {
+				t.Errorf("condition = %s/%s, want %s/%s", condition.Status, condition.Reason, tt.wantStatus, tt.wantReason)
+			}
+			if ready != tt.wantReady {
+				t.Errorf("ready = %v, want %v", ready, tt.wantReady)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ServiceAccount builds the ServiceAccount named name of a component, it is nil when the component uses an existing one.
+func ServiceAccount(instance client.Object, name string, serviceAccount apolloiov1alpha1.ServiceAccount) *corev1.ServiceAccount {
+	if serviceAccount.Name != "" {
+		return nil
+	}
+	automount := serviceAccount.AutomountServiceAccountToken
+	return &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   instance.GetNamespace(),
+			Labels:      Labels(instance, name, []string{}),
+			Annotations: serviceAccount.Annotations,
+		},
+		AutomountServiceAccountToken: &automount,
+	}
+}
+
+// ApplyServiceAccount runs the pods of template as the ServiceAccount of a component, the existing one or the one named name.
+func ApplyServiceAccount(template *corev1.PodTemplateSpec, name string, serviceAccount apolloiov1alpha1.ServiceAccount) {
+	template.Spec.ServiceAccountName = name
+	if serviceAccount.Name != "" {
+		template.Spec.ServiceAccountName = serviceAccount.Name
+	}
+	// NOTE 使用已有的serviceaccount时也不挂载token
+	automount := serviceAccount.AutomountServiceAccountToken
+	template.Spec.AutomountServiceAccountToken = &automount
+}
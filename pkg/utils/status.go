@@ -0,0 +1,190 @@
+package utils
+
+import (
+	apolloiov1alpha1 "apolloconfig.com/apollo-operator/api/v1alpha1"
+	"context"
+	"fmt"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"net"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
+	"time"
+)
+
+// databaseDialTimeout bounds the time spent checking whether a database is reachable.
+const databaseDialTimeout = 3 * time.Second
+
+// ServiceURL builds the in-cluster http address of a service.
+func ServiceURL(name, namespace string, port int32, contextPath string) string {
+	return fmt.Sprintf("http://%s.%s:%d%s", name, namespace, port, contextPath)
+}
+
+// DeploymentStatus fetches the named deployment and summarizes it as a ComponentStatus.
+// A deployment that does not exist yet is reported with a nil deployment and no error.
+func DeploymentStatus(ctx context.Context, c client.Client, namespace, name, url string) (apolloiov1alpha1.ComponentStatus, *appsv1.Deployment, error) {
+	status := apolloiov1alpha1.ComponentStatus{URL: url}
+
+	deployment := &appsv1.Deployment{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, deployment); err != nil {
+		if k8serrors.IsNotFound(err) {
+			status.Ready = "0/0"
+			return status, nil, nil
+		}
+		return status, nil, fmt.Errorf("failed to get deployment %s: %w", name, err)
+	}
+
+	status.Replicas = desiredReplicas(deployment)
+	status.ReadyReplicas = deployment.Status.ReadyReplicas
+	status.Ready = fmt.Sprintf("%d/%d", status.ReadyReplicas, status.Replicas)
+	return status, deployment, nil
+}
+
+// CheckTCP reports whether a TCP connection to host:port can be established.
+func CheckTCP(ctx context.Context, host string, port int32) error {
+	if host == "" {
+		return fmt.Errorf("database host is empty")
+	}
+	ctx, cancel := context.WithTimeout(ctx, databaseDialTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, fmt.Sprint(port)))
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// SetStatusConditions computes the Ready, Progressing, Degraded and DatabaseReachable conditions
+// from the observed deployments and the result of the database check.
+// A nil entry in deployments means the deployment has not been created yet.
+func SetStatusConditions(conditions *[]metav1.Condition, generation int64, deployments []*appsv1.Deployment, dbErr error) {
+	var progressing, failed []string
+	for _, deployment := range deployments {
+		if deployment == nil {
+			progressing = append(progressing, "<pending>")
+			continue
+		}
+		if reason := deploymentFailure(deployment); reason != "" {
+			failed = append(failed, fmt.Sprintf("%s: %s", deployment.Name, reason))
+			continue
+		}
+		if !deploymentAvailable(deployment) {
+			progressing = append(progressing, deployment.Name)
+		}
+	}
+
+	if dbErr != nil {
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               apolloiov1alpha1.ConditionDatabaseReachable,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             apolloiov1alpha1.ReasonDatabaseUnreachable,
+			Message:            dbErr.Error(),
+		})
+	} else {
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               apolloiov1alpha1.ConditionDatabaseReachable,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: generation,
+			Reason:             apolloiov1alpha1.ReasonDatabaseReachable,
+			Message:            "database is reachable",
+		})
+	}
+
+	if len(progressing) > 0 {
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               apolloiov1alpha1.ConditionProgressing,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: generation,
+			Reason:             apolloiov1alpha1.ReasonRolloutInProgress,
+			Message:            "waiting for deployments: " + strings.Join(progressing, ", "),
+		})
+	} else {
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               apolloiov1alpha1.ConditionProgressing,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             apolloiov1alpha1.ReasonRolloutComplete,
+			Message:            "all deployments are rolled out",
+		})
+	}
+
+	switch {
+	case len(failed) > 0:
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               apolloiov1alpha1.ConditionDegraded,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: generation,
+			Reason:             apolloiov1alpha1.ReasonRolloutFailed,
+			Message:            strings.Join(failed, "; "),
+		})
+	case dbErr != nil:
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               apolloiov1alpha1.ConditionDegraded,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: generation,
+			Reason:             apolloiov1alpha1.ReasonDatabaseUnreachable,
+			Message:            dbErr.Error(),
+		})
+	default:
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               apolloiov1alpha1.ConditionDegraded,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             apolloiov1alpha1.ReasonAsExpected,
+			Message:            "no failures observed",
+		})
+	}
+
+	if len(progressing) == 0 && len(failed) == 0 && dbErr == nil {
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               apolloiov1alpha1.ConditionReady,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: generation,
+			Reason:             apolloiov1alpha1.ReasonComponentsReady,
+			Message:            "all components are available",
+		})
+	} else {
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               apolloiov1alpha1.ConditionReady,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             apolloiov1alpha1.ReasonComponentsNotReady,
+			Message:            "some components are not available yet",
+		})
+	}
+}
+
+func desiredReplicas(deployment *appsv1.Deployment) int32 {
+	if deployment.Spec.Replicas == nil {
+		return 1
+	}
+	return *deployment.Spec.Replicas
+}
+
+// deploymentAvailable reports whether the latest revision of the deployment is fully rolled out.
+func deploymentAvailable(deployment *appsv1.Deployment) bool {
+	desired := desiredReplicas(deployment)
+	return deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas >= desired &&
+		deployment.Status.AvailableReplicas >= desired
+}
+
+// deploymentFailure returns the reason the deployment failed to roll out, or "" if it did not.
+func deploymentFailure(deployment *appsv1.Deployment) string {
+	for _, condition := range deployment.Status.Conditions {
+		switch {
+		case condition.Type == appsv1.DeploymentProgressing && condition.Status == corev1.ConditionFalse:
+			return condition.Message
+		case condition.Type == appsv1.DeploymentReplicaFailure && condition.Status == corev1.ConditionTrue:
+			return condition.Message
+		}
+	}
+	return ""
+}